@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/coolbit-in/docker-mcp/pkg/audit"
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+	"github.com/coolbit-in/docker-mcp/pkg/policy"
+	"github.com/coolbit-in/docker-mcp/pkg/redact"
 	dockermcp "github.com/coolbit-in/docker-mcp/pkg/server"
-	"github.com/mark3labs/mcp-go/server"
+	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +32,32 @@ var (
 	logFormat    string
 	logLevel     string
 	logFile      string
+
+	transport       string
+	listenAddr      string
+	basePath        string
+	shutdownTimeout time.Duration
+
+	authTokenFile  string
+	tlsCertFile    string
+	tlsKeyFile     string
+	tlsClientCA    string
+	tlsClientCNMap string
+
+	maxBuildContextSize string
+	scopeLabel          string
+	policyFile          string
+	dryRun              bool
+	redactKeys          []string
+
+	auditLog           string
+	auditLogMaxSize    string
+	auditLogMaxBackups int
+
+	readOnly   bool
+	allowTools []string
+	denyTools  []string
+	toolConfig string
 )
 
 // initRootCmd initializes the root command with all its flags and subcommands
@@ -49,6 +84,37 @@ func initRootCmd() *cobra.Command {
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level (debug, info, warn, error)")
 	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", getDefaultLogPath(), "Log file path")
 
+	// Add transport flags
+	rootCmd.Flags().StringVar(&transport, "transport", dockermcp.TransportStdio, "Transport to serve MCP over (stdio, sse, streamable-http)")
+	rootCmd.Flags().StringVar(&listenAddr, "listen", "127.0.0.1:8080", "Listen address for the sse and streamable-http transports")
+	rootCmd.Flags().StringVar(&basePath, "base-path", "", "Path prefix for the sse and streamable-http endpoints")
+	rootCmd.Flags().DurationVar(&shutdownTimeout, "shutdown-timeout", 10*time.Second, "Maximum time to wait for in-flight requests on shutdown")
+
+	// Add authentication flags for the network transports
+	rootCmd.Flags().StringVar(&authTokenFile, "auth-token-file", "", "File of static bearer tokens, one <principal>:<token> per line")
+	rootCmd.Flags().StringVar(&tlsCertFile, "tls-cert", "", "TLS certificate file for the sse and streamable-http transports")
+	rootCmd.Flags().StringVar(&tlsKeyFile, "tls-key", "", "TLS private key file for the sse and streamable-http transports")
+	rootCmd.Flags().StringVar(&tlsClientCA, "tls-client-ca", "", "CA bundle for verifying client certificates (enables mutual TLS)")
+	rootCmd.Flags().StringVar(&tlsClientCNMap, "tls-client-cn-map", "", "File mapping client certificate CNs to principals, one <cn>:<principal> per line")
+
+	// Add limits for tool operations
+	rootCmd.Flags().StringVar(&maxBuildContextSize, "max-build-context-size", "512MiB", "Largest local build context build_image uploads to the daemon (e.g. 100MiB, 2GiB)")
+	rootCmd.Flags().StringVar(&scopeLabel, "scope-label", "", "Only manage containers carrying this key=value label, which create_container stamps on new containers; {principal} in the value expands to the caller")
+	rootCmd.Flags().StringVar(&policyFile, "policy", "", "YAML or JSON safety policy checked before create_container, update_container, create_volume, exec_command and build_image")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Plan calls that accept dry_run without performing them and refuse every other mutating tool")
+	rootCmd.Flags().StringSliceVar(&redactKeys, "redact-keys", redact.DefaultKeyPatterns, "Case-insensitive patterns of variable names whose values are masked in inspect, logs, exec and process output and in the audit log")
+
+	// Add audit flags
+	rootCmd.Flags().StringVar(&auditLog, "audit-log", "", "Append a JSON line for every tool call to this file")
+	rootCmd.Flags().StringVar(&auditLogMaxSize, "audit-log-max-size", "100MiB", "Rotate the audit log once it reaches this size")
+	rootCmd.Flags().IntVar(&auditLogMaxBackups, "audit-log-max-backups", 5, "Rotated audit logs to keep (0 keeps all)")
+
+	// Add tool selection flags
+	rootCmd.Flags().BoolVar(&readOnly, "read-only", false, "Expose only tools that do not change daemon state (list, inspect, logs, search)")
+	rootCmd.Flags().StringSliceVar(&allowTools, "allow-tools", nil, "Comma-separated tools to expose; all others are disabled")
+	rootCmd.Flags().StringSliceVar(&denyTools, "deny-tools", nil, "Comma-separated tools to disable")
+	rootCmd.Flags().StringVar(&toolConfig, "tool-config", "", "YAML or JSON file with read_only, allow and deny tool settings, merged with the flags")
+
 	// Add version flag that displays extended version information
 	rootCmd.SetVersionTemplate(`{{with .Name}}{{printf "%s " .}}{{end}}{{printf "version %s" .Version}}
 Build Date: ` + BuildDate + `
@@ -115,8 +181,16 @@ func setupLogging() {
 
 // runMCP is the main function that starts the Docker MCP server
 func runMCP() error {
+	opts, err := serverOptions()
+	if err != nil {
+		return err
+	}
+	if opts.Audit != nil {
+		defer opts.Audit.Close()
+	}
+
 	// Create Docker MCP server with the specified socket path
-	dockerMCP, err := dockermcp.NewDockerMCPServer(dockerSocket)
+	dockerMCP, err := dockermcp.NewDockerMCPServer(dockerSocket, opts)
 	if err != nil {
 		return fmt.Errorf("failed to create Docker MCP server: %w", err)
 	}
@@ -126,16 +200,147 @@ func runMCP() error {
 		"log_format", logFormat,
 		"log_level", logLevel,
 		"log_file", logFile,
+		"transport", transport,
+		"read_only", opts.Tools.ReadOnly,
+		"dry_run", opts.Handlers.DryRun,
+		"scope_label", opts.Handlers.ScopeLabel,
+		"policy", policyFile,
+		"audit_log", auditLog,
 	)
 
-	// Start MCP server
-	if err := server.ServeStdio(dockerMCP.GetMCPServer()); err != nil {
+	// Cancel the context on SIGINT/SIGTERM so network transports can shut down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	httpOpts := dockermcp.HTTPOptions{
+		Addr:            listenAddr,
+		BasePath:        basePath,
+		ShutdownTimeout: shutdownTimeout,
+	}
+	if transport != dockermcp.TransportStdio {
+		if err := configureAuth(&httpOpts); err != nil {
+			return err
+		}
+	}
+
+	// Start MCP server on the selected transport
+	switch transport {
+	case dockermcp.TransportStdio:
+		err = dockerMCP.ServeStdio()
+	case dockermcp.TransportSSE:
+		err = dockerMCP.ServeSSE(ctx, httpOpts)
+	case dockermcp.TransportStreamableHTTP:
+		err = dockerMCP.ServeStreamableHTTP(ctx, httpOpts)
+	default:
+		return fmt.Errorf("unsupported transport: %s", transport)
+	}
+	if err != nil {
 		return fmt.Errorf("server error: %w", err)
 	}
 
 	return nil
 }
 
+// serverOptions builds the server configuration from command line flags
+func serverOptions() (dockermcp.Options, error) {
+	var opts dockermcp.Options
+
+	size, err := units.RAMInBytes(maxBuildContextSize)
+	if err != nil || size <= 0 {
+		return opts, fmt.Errorf("invalid --max-build-context-size %q", maxBuildContextSize)
+	}
+	opts.Handlers.MaxBuildContextSize = size
+
+	if scopeLabel != "" {
+		if key, _, ok := strings.Cut(scopeLabel, "="); !ok || key == "" {
+			return opts, fmt.Errorf("invalid --scope-label %q: expected key=value", scopeLabel)
+		}
+		opts.Handlers.ScopeLabel = scopeLabel
+	}
+
+	opts.Handlers.DryRun = dryRun
+
+	redactor, err := redact.New(redactKeys)
+	if err != nil {
+		return opts, err
+	}
+	opts.Handlers.Redactor = redactor
+
+	if policyFile != "" {
+		p, err := policy.Load(policyFile)
+		if err != nil {
+			return opts, err
+		}
+		opts.Handlers.Policy = p
+	}
+
+	opts.Tools = dockermcp.ToolFilter{ReadOnly: readOnly, Allow: allowTools, Deny: denyTools}
+	if toolConfig != "" {
+		fileFilter, err := dockermcp.LoadToolFilter(toolConfig)
+		if err != nil {
+			return opts, err
+		}
+		opts.Tools = opts.Tools.Merge(fileFilter)
+	}
+
+	// Opened last so no other error leaves the file open
+	if auditLog != "" {
+		maxSize, err := units.RAMInBytes(auditLogMaxSize)
+		if err != nil || maxSize <= 0 {
+			return opts, fmt.Errorf("invalid --audit-log-max-size %q", auditLogMaxSize)
+		}
+		logger, err := audit.Open(auditLog, audit.Options{MaxSize: maxSize, MaxBackups: auditLogMaxBackups})
+		if err != nil {
+			return opts, err
+		}
+		opts.Audit = logger
+	}
+
+	return opts, nil
+}
+
+// configureAuth sets up TLS and authenticators for the network transports from command line flags
+func configureAuth(opts *dockermcp.HTTPOptions) error {
+	var authenticators []auth.Authenticator
+
+	if tlsCertFile != "" || tlsKeyFile != "" {
+		tlsConfig, err := auth.ServerTLSConfig(tlsCertFile, tlsKeyFile, tlsClientCA)
+		if err != nil {
+			return err
+		}
+		opts.TLSConfig = tlsConfig
+	} else if tlsClientCA != "" {
+		return fmt.Errorf("--tls-client-ca requires --tls-cert and --tls-key")
+	}
+
+	if tlsClientCA != "" {
+		var cnMap map[string]string
+		if tlsClientCNMap != "" {
+			var err error
+			cnMap, err = auth.LoadCNMapFile(tlsClientCNMap)
+			if err != nil {
+				return err
+			}
+		}
+		authenticators = append(authenticators, auth.NewMTLSAuthenticator(cnMap))
+	} else if tlsClientCNMap != "" {
+		return fmt.Errorf("--tls-client-cn-map requires --tls-client-ca")
+	}
+
+	if authTokenFile != "" {
+		tokenAuth, err := auth.LoadTokenFile(authTokenFile)
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, tokenAuth)
+	}
+
+	if len(authenticators) > 0 {
+		opts.Authenticator = auth.Chain(authenticators...)
+	}
+	return nil
+}
+
 // getDefaultLogPath returns the default path for log file
 func getDefaultLogPath() string {
 	home, err := os.UserHomeDir()
@@ -0,0 +1,143 @@
+// Package audit records tool invocations as append-only JSON lines in a
+// size-rotated file.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+)
+
+// Record is one tool invocation
+type Record struct {
+	Time       time.Time              `json:"time"`                // When the call started
+	Principal  *auth.Principal        `json:"principal,omitempty"` // Authenticated caller
+	Tool       string                 `json:"tool"`                // Tool name
+	Arguments  map[string]interface{} `json:"arguments,omitempty"` // Sanitized call arguments
+	Targets    []string               `json:"targets,omitempty"`   // IDs and names of the objects acted on
+	DurationMS float64                `json:"duration_ms"`         // Time taken by the call in milliseconds
+	Success    bool                   `json:"success"`             // Whether the call succeeded
+	Error      string                 `json:"error,omitempty"`     // Error message of a failed call
+}
+
+// Options configures rotation of the audit file. The zero value never rotates.
+type Options struct {
+	MaxSize    int64 // Rotate once the file would grow past this many bytes; <= 0 disables rotation
+	MaxBackups int   // Rotated files to keep, newest first as path.1, path.2, ...; <= 0 keeps all
+}
+
+// Logger appends records to an audit file. It is safe for concurrent use.
+type Logger struct {
+	mu      sync.Mutex
+	path    string
+	options Options
+	file    *os.File
+	size    int64
+}
+
+// Open opens the audit file for appending, creating it and its directory if needed
+func Open(path string, options Options) (*Logger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	l := &Logger{path: path, options: options}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// open opens the current audit file and records its size
+func (l *Logger) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// Log appends a record as one JSON line, rotating the file first if the line would exceed the maximum size
+func (l *Logger) Log(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+	if l.options.MaxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.options.MaxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts the backups up by one, moves the current file to path.1 and starts a new file
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	l.file = nil
+
+	// Find the first free backup slot, or the oldest one kept
+	last := 1
+	for ; l.options.MaxBackups <= 0 || last < l.options.MaxBackups; last++ {
+		if _, err := os.Stat(l.backup(last)); os.IsNotExist(err) {
+			break
+		}
+	}
+	for i := last; i > 1; i-- {
+		if err := os.Rename(l.backup(i-1), l.backup(i)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.backup(1)); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	return l.open()
+}
+
+// backup returns the path of the n-th rotated file
+func (l *Logger) backup(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// Close closes the audit file
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,235 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/coolbit-in/docker-mcp/pkg/redact"
+)
+
+// readTools returns the tool names of the records in an audit file
+func readTools(t *testing.T, path string) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var tools []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		tools = append(tools, record.Tool)
+	}
+	return tools
+}
+
+// lineSize is the length of the audit line of a record whose tool name has one character
+func lineSize(t *testing.T) int64 {
+	t.Helper()
+
+	line, err := json.Marshal(Record{Tool: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int64(len(line)) + 1
+}
+
+func TestRotation(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBackups int
+		records    int
+		files      [][]string // Tools in the current file, then in path.1, path.2, ...
+	}{
+		{
+			name:    "no rotation needed",
+			records: 2,
+			files:   [][]string{{"a", "b"}},
+		},
+		{
+			name:    "unlimited backups",
+			records: 7,
+			files:   [][]string{{"g"}, {"e", "f"}, {"c", "d"}, {"a", "b"}},
+		},
+		{
+			name:       "oldest backups dropped",
+			maxBackups: 2,
+			records:    9,
+			files:      [][]string{{"i"}, {"g", "h"}, {"e", "f"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logs", "audit.log")
+			logger, err := Open(path, Options{MaxSize: 2 * lineSize(t), MaxBackups: tt.maxBackups})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tt.records; i++ {
+				if err := logger.Log(Record{Tool: string(rune('a' + i))}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := logger.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, want := range tt.files {
+				file := path
+				if i > 0 {
+					file = logger.backup(i)
+				}
+				if got := readTools(t, file); !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: got %v, want %v", file, got, want)
+				}
+			}
+			if _, err := os.Stat(logger.backup(len(tt.files))); !os.IsNotExist(err) {
+				t.Errorf("unexpected backup %s", logger.backup(len(tt.files)))
+			}
+		})
+	}
+}
+
+func TestReopenAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, tool := range []string{"a", "b", "c"} {
+		logger, err := Open(path, Options{MaxSize: 2 * lineSize(t)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Log(Record{Tool: tool}); err != nil {
+			t.Fatal(err)
+		}
+		logger.Close()
+	}
+
+	// The size of the existing file counts towards the limit
+	if got := readTools(t, path); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("current file: got %v", got)
+	}
+	if got := readTools(t, path+".1"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("backup: got %v", got)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("audit file mode %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestConcurrentLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := Open(path, Options{MaxSize: 10 * lineSize(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := logger.Log(Record{Tool: "x"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	logger.Close()
+
+	total := 0
+	for _, file := range append([]string{path}, logger.backup(1), logger.backup(2), logger.backup(3), logger.backup(4)) {
+		total += len(readTools(t, file))
+	}
+	if total != 50 {
+		t.Errorf("got %d records, want 50", total)
+	}
+}
+
+func TestLogAfterClose(t *testing.T) {
+	logger, err := Open(filepath.Join(t.TempDir(), "audit.log"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if err := logger.Log(Record{Tool: "a"}); err == nil || err.Error() != "audit log is closed" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	redactor := redact.Default()
+	long := strings.Repeat("x", maxValueLength-1) + "é" + "tail"
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "empty",
+			args: map[string]interface{}{},
+			want: nil,
+		},
+		{
+			name: "secret variables",
+			args: map[string]interface{}{"env": []interface{}{"DB_PASSWORD=hunter2", "MODE=prod"}, "image": "nginx"},
+			want: map[string]interface{}{"env": []interface{}{"DB_PASSWORD=[REDACTED]", "MODE=prod"}, "image": "nginx"},
+		},
+		{
+			name: "secret map keys",
+			args: map[string]interface{}{"build_args": map[string]interface{}{"NPM_TOKEN": "abc", "VERSION": "1.0"}},
+			want: map[string]interface{}{"build_args": map[string]interface{}{"NPM_TOKEN": "[REDACTED]", "VERSION": "1.0"}},
+		},
+		{
+			name: "credentials in commands",
+			args: map[string]interface{}{"command": "psql postgres://app:hunter2@db/app", "privileged": true, "tail": 10.0},
+			want: map[string]interface{}{"command": "psql postgres://app:[REDACTED]@db/app", "privileged": true, "tail": 10.0},
+		},
+		{
+			name: "long strings",
+			args: map[string]interface{}{"dockerfile_content": long},
+			want: map[string]interface{}{"dockerfile_content": strings.Repeat("x", maxValueLength-1) + "...[truncated, 261 bytes]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.args, redactor); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The arguments passed to the tool are left untouched
+	args := map[string]interface{}{"env": []interface{}{"DB_PASSWORD=hunter2"}}
+	Sanitize(args, redactor)
+	if args["env"].([]interface{})[0] != "DB_PASSWORD=hunter2" {
+		t.Errorf("arguments modified: %v", args)
+	}
+
+	// Without a redactor only long strings are changed
+	got := Sanitize(map[string]interface{}{"env": []interface{}{"DB_PASSWORD=hunter2"}}, nil)
+	if got["env"].([]interface{})[0] != "DB_PASSWORD=hunter2" {
+		t.Errorf("unexpected result %v", got)
+	}
+}
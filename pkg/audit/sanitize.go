@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/coolbit-in/docker-mcp/pkg/redact"
+)
+
+// maxValueLength is the longest string argument recorded in full
+const maxValueLength = 256
+
+// Sanitize returns a copy of tool arguments fit for the audit log. Secrets
+// are masked by the redactor the tool output uses, including the values of
+// map entries under secret keys such as build args, and long strings such as
+// inline file contents are truncated.
+func Sanitize(args map[string]interface{}, redactor *redact.Redactor) map[string]interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	return sanitizeValue(args, redactor).(map[string]interface{})
+}
+
+// sanitizeValue sanitizes one decoded JSON value
+func sanitizeValue(value interface{}, redactor *redact.Redactor) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if redactor.SecretKey(key) {
+				result[key] = redact.Mask
+				continue
+			}
+			result[key] = sanitizeValue(item, redactor)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = sanitizeValue(item, redactor)
+		}
+		return result
+	case string:
+		v = redactor.String(v)
+		if len(v) > maxValueLength {
+			cut := maxValueLength
+			for cut > 0 && !utf8.RuneStart(v[cut]) {
+				cut--
+			}
+			return fmt.Sprintf("%s...[truncated, %d bytes]", v[:cut], len(v))
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,104 @@
+// Package auth provides caller authentication for the network transports
+// and carries the authenticated principal through request contexts.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Authentication methods recorded on a Principal
+const (
+	MethodNone   = "none"
+	MethodLocal  = "local"
+	MethodBearer = "bearer"
+	MethodMTLS   = "mtls"
+)
+
+// ErrUnauthenticated is returned when a request carries no usable credentials
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Principal identifies the caller of an MCP request
+type Principal struct {
+	Name   string `json:"name"`   // Caller identity (token name or certificate CN mapping)
+	Method string `json:"method"` // Authentication method used
+}
+
+// String returns the principal in "method:name" form for logging
+func (p *Principal) String() string {
+	if p == nil {
+		return ""
+	}
+	return p.Method + ":" + p.Name
+}
+
+// Anonymous is the principal used by network transports without authentication
+var Anonymous = &Principal{Name: "anonymous", Method: MethodNone}
+
+// Local is the principal used by the stdio transport, whose caller is the parent process
+var Local = &Principal{Name: "local", Method: MethodLocal}
+
+// Authenticator resolves the principal behind an HTTP request
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// principalKey is the context key for the authenticated principal
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// chain tries each authenticator in turn and returns the first success
+type chain []Authenticator
+
+// Chain combines authenticators; a request is accepted if any of them accepts it
+func Chain(authenticators ...Authenticator) Authenticator {
+	if len(authenticators) == 1 {
+		return authenticators[0]
+	}
+	return chain(authenticators)
+}
+
+// Authenticate implements Authenticator
+func (c chain) Authenticate(r *http.Request) (*Principal, error) {
+	err := ErrUnauthenticated
+	for _, a := range c {
+		p, aerr := a.Authenticate(r)
+		if aerr == nil {
+			return p, nil
+		}
+		// Prefer a specific rejection over "no credentials"
+		if !errors.Is(aerr, ErrUnauthenticated) {
+			err = aerr
+		}
+	}
+	return nil, err
+}
+
+// Middleware authenticates every request with a and stores the principal in the request context.
+// A nil authenticator lets all requests through as Anonymous.
+func Middleware(a Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := Anonymous
+		if a != nil {
+			var err error
+			p, err = a.Authenticate(r)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="docker-mcp"`)
+				http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+	})
+}
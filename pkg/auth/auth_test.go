@@ -0,0 +1,331 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+)
+
+// writeTokenFile writes a token file for alice and bob and returns an authenticator for it
+func writeTokenFile(t *testing.T) *auth.TokenAuthenticator {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tokens")
+	content := "# agents\nalice:alice-token\n\n  bob : bob-token  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := auth.LoadTokenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// TestTokenAuthenticator checks token file parsing and bearer token matching
+func TestTokenAuthenticator(t *testing.T) {
+	a := writeTokenFile(t)
+
+	cases := []struct {
+		header string
+		want   string
+		err    string
+	}{
+		{header: "Bearer alice-token", want: "bearer:alice"},
+		{header: "bearer  bob-token ", want: "bearer:bob"},
+		{header: "", err: auth.ErrUnauthenticated.Error()},
+		{header: "Basic YWxpY2U6eA==", err: auth.ErrUnauthenticated.Error()},
+		{header: "Bearer alice-token2", err: "invalid bearer token"},
+		{header: "Bearer alice", err: "invalid bearer token"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+		p, err := a.Authenticate(req)
+		switch {
+		case c.err != "" && (err == nil || err.Error() != c.err):
+			t.Errorf("%q: expected error %q, got %v", c.header, c.err, err)
+		case c.err == "" && (err != nil || p.String() != c.want):
+			t.Errorf("%q: expected %s, got %v %v", c.header, c.want, p, err)
+		}
+	}
+
+	// A rejected token wins over missing credentials in a chain
+	chain := auth.Chain(auth.NewMTLSAuthenticator(nil), a)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	if _, err := chain.Authenticate(req); err == nil || errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("expected the chain to report the invalid token, got %v", err)
+	}
+
+	dir := t.TempDir()
+	for content, want := range map[string]string{
+		"alice:one\nbob\n":     "invalid token file entry on line 2",
+		"alice:\n":             "invalid token file entry on line 1",
+		"# only a comment\n\n": "contains no tokens",
+	} {
+		path := filepath.Join(dir, "tokens")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := auth.LoadTokenFile(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error %q, got %v", content, want, err)
+		}
+	}
+}
+
+// testCA issues certificates for mutual TLS tests
+type testCA struct {
+	t    *testing.T
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newTestCA creates a self-signed certificate authority
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{t: t, cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue creates a certificate and key, in PEM form, for a client or for a server on 127.0.0.1
+func (ca *testCA) issue(cn string, server bool) (certPEM, keyPEM []byte) {
+	ca.t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		ca.t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		ca.t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if server {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		ca.t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		ca.t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// TestMTLSAuthenticator checks that verified client certificates map to principals by CN
+func TestMTLSAuthenticator(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	serverCert, serverKey := ca.issue("docker-mcp", true)
+	files := map[string][]byte{"server.pem": serverCert, "server.key": serverKey, "ca.pem": ca.pem}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tlsConfig, err := auth.ServerTLSConfig(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Common names may contain colons; the principal follows the last one
+	cnMapPath := filepath.Join(dir, "cn-map")
+	if err := os.WriteFile(cnMapPath, []byte("# agents\nci-runner:ci\nspiffe://example/agent:agent\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cnMap, err := auth.LoadCNMapFile(cnMapPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cnMap["spiffe://example/agent"] != "agent" {
+		t.Fatalf("unexpected CN map %v", cnMap)
+	}
+
+	whoami := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := auth.FromContext(r.Context())
+		fmt.Fprint(w, p.String())
+	})
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	// get requests the server as the holder of a certificate with the given CN
+	get := func(url, cn string) (int, string) {
+		t.Helper()
+		clientConfig := &tls.Config{RootCAs: roots}
+		if cn != "" {
+			certPEM, keyPEM := ca.issue(cn, false)
+			cert, err := tls.X509KeyPair(certPEM, keyPEM)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clientConfig.Certificates = []tls.Certificate{cert}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		resp, err := client.Get(url)
+		if err != nil {
+			return 0, err.Error()
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, strings.TrimSpace(string(body))
+	}
+
+	mapped := httptest.NewUnstartedServer(auth.Middleware(auth.NewMTLSAuthenticator(cnMap), whoami))
+	mapped.TLS = tlsConfig
+	mapped.Config.ErrorLog = log.New(io.Discard, "", 0) // expected handshake failures
+	mapped.StartTLS()
+	t.Cleanup(mapped.Close)
+
+	if status, body := get(mapped.URL, "ci-runner"); status != http.StatusOK || body != "mtls:ci" {
+		t.Fatalf("expected the mapped principal, got %d %q", status, body)
+	}
+	if status, body := get(mapped.URL, "intruder"); status != http.StatusUnauthorized || !strings.Contains(body, `client certificate CN "intruder" is not authorized`) {
+		t.Fatalf("expected an unmapped CN to be refused, got %d %q", status, body)
+	}
+	if status, _ := get(mapped.URL, ""); status != 0 {
+		t.Fatalf("expected the handshake to fail without a client certificate, got %d", status)
+	}
+
+	// Without a mapping every verified certificate is accepted under its CN
+	open := httptest.NewUnstartedServer(auth.Middleware(auth.NewMTLSAuthenticator(nil), whoami))
+	open.TLS = tlsConfig
+	open.StartTLS()
+	t.Cleanup(open.Close)
+
+	if status, body := get(open.URL, "intruder"); status != http.StatusOK || body != "mtls:intruder" {
+		t.Fatalf("expected the CN as principal, got %d %q", status, body)
+	}
+}
+
+// staticAuthenticator returns a fixed principal or error
+type staticAuthenticator struct {
+	principal *auth.Principal
+	err       error
+}
+
+// Authenticate implements auth.Authenticator
+func (s staticAuthenticator) Authenticate(*http.Request) (*auth.Principal, error) {
+	return s.principal, s.err
+}
+
+// TestChain checks that a chain accepts the first success and reports the most specific rejection
+func TestChain(t *testing.T) {
+	alice := staticAuthenticator{principal: &auth.Principal{Name: "alice", Method: auth.MethodBearer}}
+	none := staticAuthenticator{err: auth.ErrUnauthenticated}
+	wrapped := staticAuthenticator{err: fmt.Errorf("no certificate: %w", auth.ErrUnauthenticated)}
+	rejected := staticAuthenticator{err: errors.New("invalid bearer token")}
+
+	cases := []struct {
+		name           string
+		authenticators []auth.Authenticator
+		want           string
+		err            string
+	}{
+		{name: "single", authenticators: []auth.Authenticator{alice}, want: "bearer:alice"},
+		{name: "first success", authenticators: []auth.Authenticator{none, alice, rejected}, want: "bearer:alice"},
+		{name: "no credentials", authenticators: []auth.Authenticator{none, wrapped}, err: "unauthenticated"},
+		{name: "rejection wins", authenticators: []auth.Authenticator{rejected, none}, err: "invalid bearer token"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, err := auth.Chain(c.authenticators...).Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+			switch {
+			case c.err != "" && (err == nil || err.Error() != c.err):
+				t.Errorf("expected error %q, got %v", c.err, err)
+			case c.err == "" && (err != nil || p.String() != c.want):
+				t.Errorf("expected %s, got %v %v", c.want, p, err)
+			}
+		})
+	}
+}
+
+// TestMiddleware checks that the principal reaches the handler and that rejected requests do not
+func TestMiddleware(t *testing.T) {
+	whoami := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := auth.FromContext(r.Context())
+		fmt.Fprint(w, p.String())
+	})
+
+	cases := []struct {
+		name   string
+		auth   auth.Authenticator
+		header string
+		status int
+		body   string
+	}{
+		{name: "no authenticator", status: http.StatusOK, body: "none:anonymous"},
+		{name: "accepted", auth: writeTokenFile(t), header: "Bearer bob-token", status: http.StatusOK, body: "bearer:bob"},
+		{name: "rejected", auth: writeTokenFile(t), header: "Bearer nope", status: http.StatusUnauthorized, body: "Unauthorized: invalid bearer token"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.header != "" {
+				req.Header.Set("Authorization", c.header)
+			}
+			rec := httptest.NewRecorder()
+			auth.Middleware(c.auth, whoami).ServeHTTP(rec, req)
+			if rec.Code != c.status || strings.TrimSpace(rec.Body.String()) != c.body {
+				t.Errorf("expected %d %q, got %d %q", c.status, c.body, rec.Code, rec.Body.String())
+			}
+			if c.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// MTLSAuthenticator authenticates requests by their verified client certificate
+type MTLSAuthenticator struct {
+	// cnMap maps certificate common names to principal names.
+	// When empty, any verified certificate is accepted and its CN is the principal.
+	cnMap map[string]string
+}
+
+// NewMTLSAuthenticator creates an mTLS authenticator with an optional CN mapping
+func NewMTLSAuthenticator(cnMap map[string]string) *MTLSAuthenticator {
+	return &MTLSAuthenticator{cnMap: cnMap}
+}
+
+// LoadCNMapFile reads a CN mapping from path.
+// Each non-empty line has the form "<common name>:<principal>"; lines starting with '#' are ignored.
+func LoadCNMapFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CN map file: %w", err)
+	}
+	defer f.Close()
+
+	cnMap := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Split on the last colon so common names may contain colons
+		idx := strings.LastIndex(line, ":")
+		if idx <= 0 || idx == len(line)-1 {
+			return nil, fmt.Errorf("invalid CN map entry on line %d: expected <common name>:<principal>", lineNo)
+		}
+		cnMap[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CN map file: %w", err)
+	}
+
+	return cnMap, nil
+}
+
+// Authenticate implements Authenticator
+func (a *MTLSAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, ErrUnauthenticated
+	}
+
+	cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	if cn == "" {
+		return nil, errors.New("client certificate has no common name")
+	}
+
+	name := cn
+	if len(a.cnMap) > 0 {
+		mapped, ok := a.cnMap[cn]
+		if !ok {
+			return nil, fmt.Errorf("client certificate CN %q is not authorized", cn)
+		}
+		name = mapped
+	}
+
+	return &Principal{Name: name, Method: MethodMTLS}, nil
+}
+
+// ServerTLSConfig builds a TLS configuration from a certificate and key.
+// If clientCAFile is set, clients must present a certificate signed by one of its CAs.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		caPEM, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
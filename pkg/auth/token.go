@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// tokenEntry holds the hash of a static token and the principal it maps to
+type tokenEntry struct {
+	hash [sha256.Size]byte
+	name string
+}
+
+// TokenAuthenticator authenticates requests carrying a static bearer token
+type TokenAuthenticator struct {
+	tokens []tokenEntry
+}
+
+// LoadTokenFile reads static bearer tokens from path.
+// Each non-empty line has the form "<principal>:<token>"; lines starting with '#' are ignored.
+func LoadTokenFile(path string) (*TokenAuthenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token file: %w", err)
+	}
+	defer f.Close()
+
+	a := &TokenAuthenticator{}
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, token, ok := strings.Cut(line, ":")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("invalid token file entry on line %d: expected <principal>:<token>", lineNo)
+		}
+		a.tokens = append(a.tokens, tokenEntry{hash: sha256.Sum256([]byte(token)), name: name})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	if len(a.tokens) == 0 {
+		return nil, fmt.Errorf("token file %s contains no tokens", path)
+	}
+
+	return a, nil
+}
+
+// Authenticate implements Authenticator
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	// Compare hashes in constant time and check every entry to avoid timing leaks
+	hash := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	var match *tokenEntry
+	for i := range a.tokens {
+		if subtle.ConstantTimeCompare(hash[:], a.tokens[i].hash[:]) == 1 {
+			match = &a.tokens[i]
+		}
+	}
+	if match == nil {
+		return nil, errors.New("invalid bearer token")
+	}
+
+	return &Principal{Name: match.name, Method: MethodBearer}, nil
+}
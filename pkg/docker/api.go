@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/go-connections/nat"
+)
+
+// API describes the Docker operations performed on behalf of MCP tools.
+// Client implements it against a Docker daemon; tests may substitute their own implementation.
+type API interface {
+	// Containers
+	ListContainers(ctx context.Context, all bool) ([]types.Container, error)
+	CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, name string) (container.CreateResponse, error)
+	StartContainer(ctx context.Context, containerID string) error
+	StopContainer(ctx context.Context, containerID string, timeout *int) error
+	RestartContainer(ctx context.Context, containerID string, timeout *int) error
+	RemoveContainer(ctx context.Context, containerID string, force, removeVolumes bool) error
+	UpdateContainer(ctx context.Context, containerID string, update container.UpdateConfig) (container.UpdateResponse, error)
+	PauseContainer(ctx context.Context, containerID string) error
+	UnpauseContainer(ctx context.Context, containerID string) error
+	KillContainer(ctx context.Context, containerID, signal string) error
+	RenameContainer(ctx context.Context, containerID, newName string) error
+	WaitContainer(ctx context.Context, containerID string, condition container.WaitCondition) (container.WaitResponse, error)
+	ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error)
+	InspectContainer(ctx context.Context, containerID string) (types.ContainerJSON, error)
+	ExecCommand(ctx context.Context, containerID string, opts ExecOptions) (ExecResult, error)
+	ContainerStats(ctx context.Context, containerID string) (container.StatsResponse, error)
+	ContainerTop(ctx context.Context, containerID string, psArgs []string) (container.TopResponse, error)
+	ContainerDiff(ctx context.Context, containerID string) ([]container.FilesystemChange, error)
+	ContainerPorts(ctx context.Context, containerID string) (nat.PortMap, error)
+
+	// Images
+	PullImage(ctx context.Context, imageName string) (io.ReadCloser, error)
+	ListImages(ctx context.Context, all bool) ([]image.Summary, error)
+	SearchImages(ctx context.Context, term string, limit int) ([]registry.SearchResult, error)
+	RemoveImage(ctx context.Context, imageID string, force bool) ([]image.DeleteResponse, error)
+	InspectImage(ctx context.Context, imageID string) (types.ImageInspect, error)
+	BuildImage(ctx context.Context, buildContext *BuildArchive, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
+
+	// Networks
+	ListNetworks(ctx context.Context, filterArgs filters.Args) ([]network.Summary, error)
+	InspectNetwork(ctx context.Context, networkID string) (network.Inspect, error)
+	CreateNetwork(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error)
+	RemoveNetwork(ctx context.Context, networkID string) error
+	PruneNetworks(ctx context.Context, filterArgs filters.Args) (network.PruneReport, error)
+	ConnectNetwork(ctx context.Context, networkID, containerID string, settings *network.EndpointSettings) error
+	DisconnectNetwork(ctx context.Context, networkID, containerID string, force bool) error
+
+	// Volumes
+	ListVolumes(ctx context.Context, filterArgs filters.Args) (volume.ListResponse, error)
+	InspectVolume(ctx context.Context, volumeName string) (volume.Volume, error)
+	CreateVolume(ctx context.Context, options volume.CreateOptions) (volume.Volume, error)
+	RemoveVolume(ctx context.Context, volumeName string, force bool) error
+	PruneVolumes(ctx context.Context, filterArgs filters.Args) (volume.PruneReport, error)
+}
+
+// Ensure Client implements API
+var _ API = (*Client)(nil)
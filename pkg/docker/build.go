@@ -0,0 +1,297 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/go-units"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+// dockerignoreFile lists the context paths excluded from a build
+const dockerignoreFile = ".dockerignore"
+
+// BuildContext describes where the files for an image build come from.
+// Exactly one of Dir, Files or RemoteURL must be set.
+type BuildContext struct {
+	Dir       string               // Directory on the server's filesystem
+	Files     map[string]BuildFile // In-memory files keyed by slash-separated relative path
+	RemoteURL string               // Git repository or tarball URL fetched by the daemon
+}
+
+// BuildFile is a file placed in an in-memory build context
+type BuildFile struct {
+	Content []byte
+	Mode    int64 // Permission bits; 0644 when zero
+}
+
+// BuildArchive is a build context ready to upload to the daemon
+type BuildArchive struct {
+	Context   io.Reader // Tar stream of the context; nil for remote contexts
+	RemoteURL string    // Remote context fetched by the daemon instead of Context
+	Size      int64     // Total size in bytes of the files sent
+	Files     int       // Number of files sent
+	Ignored   int       // Number of files excluded by .dockerignore
+}
+
+// ErrBuildContextTooLarge is returned when a local build context exceeds the configured maximum
+var ErrBuildContextTooLarge = errors.New("build context too large")
+
+// Archive prepares the context for upload. Paths matched by the context's
+// .dockerignore are left out, except the Dockerfile and .dockerignore
+// themselves, as the docker CLI does. Local contexts whose files total more
+// than maxSize bytes are rejected before anything is read into the archive;
+// maxSize <= 0 disables the check.
+func (b BuildContext) Archive(dockerfile string, maxSize int64) (*BuildArchive, error) {
+	sources := 0
+	for _, set := range []bool{b.Dir != "", len(b.Files) > 0, b.RemoteURL != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, fmt.Errorf("build context must have exactly one of a directory, files or a remote URL")
+	}
+
+	switch {
+	case b.RemoteURL != "":
+		return &BuildArchive{RemoteURL: b.RemoteURL}, nil
+	case len(b.Files) > 0:
+		return b.archiveFiles(dockerfile, maxSize)
+	default:
+		return b.archiveDir(dockerfile, maxSize)
+	}
+}
+
+// Dockerfile returns the contents of the named Dockerfile of a local context,
+// or nil for a remote context
+func (b BuildContext) Dockerfile(name string) ([]byte, error) {
+	switch {
+	case b.RemoteURL != "":
+		return nil, nil
+	case len(b.Files) > 0:
+		file, ok := b.Files[name]
+		if !ok {
+			return nil, fmt.Errorf("dockerfile %s not found in context", name)
+		}
+		return file.Content, nil
+	default:
+		content, err := os.ReadFile(filepath.Join(b.Dir, name))
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("dockerfile %s not found in context", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dockerfile: %w", err)
+		}
+		return content, nil
+	}
+}
+
+// archiveDir tars the context directory
+func (b BuildContext) archiveDir(dockerfile string, maxSize int64) (*BuildArchive, error) {
+	// Verify that the Dockerfile exists in the context
+	dockerfilePath := filepath.Join(b.Dir, dockerfile)
+	if _, err := os.Stat(dockerfilePath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("dockerfile %s not found in context", dockerfile)
+	}
+
+	var ignore io.Reader
+	f, err := os.Open(filepath.Join(b.Dir, dockerignoreFile))
+	switch {
+	case err == nil:
+		defer f.Close()
+		ignore = f
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read %s: %w", dockerignoreFile, err)
+	}
+	excludes, pm, err := ignorePatterns(ignore, dockerfile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Measure the context first so oversized contexts are rejected without being uploaded
+	result := &BuildArchive{}
+	err = filepath.WalkDir(b.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(b.Dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		if pm != nil {
+			excluded, err := pm.MatchesOrParentMatches(rel)
+			if err != nil {
+				return err
+			}
+			// Excluded directories are still walked so their files are counted
+			// and ! patterns can re-include paths inside them
+			if excluded {
+				if !d.IsDir() {
+					result.Ignored++
+				}
+				return nil
+			}
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		result.Files++
+		if info.Mode().IsRegular() {
+			result.Size += info.Size()
+		}
+		return checkContextSize(result.Size, maxSize)
+	})
+	if err != nil {
+		if errors.Is(err, ErrBuildContextTooLarge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read build context: %w", err)
+	}
+
+	// Create build context from the directory
+	tarball, err := archive.TarWithOptions(b.Dir, &archive.TarOptions{ExcludePatterns: excludes})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build context: %w", err)
+	}
+	result.Context = tarball
+	return result, nil
+}
+
+// archiveFiles packs in-memory files into a tar archive; the daemon creates parent directories
+func (b BuildContext) archiveFiles(dockerfile string, maxSize int64) (*BuildArchive, error) {
+	if _, ok := b.Files[dockerfile]; !ok {
+		return nil, fmt.Errorf("dockerfile %s not found in context", dockerfile)
+	}
+
+	var ignore io.Reader
+	if file, ok := b.Files[dockerignoreFile]; ok {
+		ignore = bytes.NewReader(file.Content)
+	}
+	_, pm, err := ignorePatterns(ignore, dockerfile)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BuildArchive{}
+	names := make([]string, 0, len(b.Files))
+	for name, file := range b.Files {
+		if err := validateContextPath(name); err != nil {
+			return nil, err
+		}
+		if pm != nil {
+			excluded, err := pm.MatchesOrParentMatches(filepath.FromSlash(name))
+			if err != nil {
+				return nil, err
+			}
+			if excluded {
+				result.Ignored++
+				continue
+			}
+		}
+		names = append(names, name)
+		result.Files++
+		result.Size += int64(len(file.Content))
+	}
+	if err := checkContextSize(result.Size, maxSize); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		file := b.Files[name]
+		mode := file.Mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		hdr := &tar.Header{
+			Name:     name,
+			Mode:     mode,
+			Size:     int64(len(file.Content)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("failed to create build context: %w", err)
+		}
+		if _, err := tw.Write(file.Content); err != nil {
+			return nil, fmt.Errorf("failed to create build context: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create build context: %w", err)
+	}
+	result.Context = &buf
+	return result, nil
+}
+
+// ignorePatterns parses .dockerignore content, keeping the Dockerfile and
+// .dockerignore in the context. The matcher is nil when nothing is excluded.
+func ignorePatterns(r io.Reader, dockerfile string) ([]string, *patternmatcher.PatternMatcher, error) {
+	excludes, err := ignorefile.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", dockerignoreFile, err)
+	}
+	if len(excludes) == 0 {
+		return nil, nil, nil
+	}
+	excludes = append(excludes, "!"+dockerignoreFile, "!"+filepath.Clean(dockerfile))
+
+	pm, err := patternmatcher.New(excludes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", dockerignoreFile, err)
+	}
+	return excludes, pm, nil
+}
+
+// checkContextSize fails once size exceeds a positive maxSize
+func checkContextSize(size, maxSize int64) error {
+	if maxSize > 0 && size > maxSize {
+		return fmt.Errorf("%w: exceeds the maximum of %s", ErrBuildContextTooLarge, units.BytesSize(float64(maxSize)))
+	}
+	return nil
+}
+
+// BuildImage uploads the archived context and starts a build
+func (c *Client) BuildImage(ctx context.Context, buildContext *BuildArchive, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	if options.Dockerfile == "" {
+		options.Dockerfile = "Dockerfile"
+	}
+	if buildContext.RemoteURL != "" {
+		options.RemoteContext = buildContext.RemoteURL
+	}
+
+	// Execute the build
+	return c.dockerClient.ImageBuild(ctx, buildContext.Context, options)
+}
+
+// validateContextPath checks that name is a clean relative path inside a build context
+func validateContextPath(name string) error {
+	if name == "" || path.IsAbs(name) || strings.Contains(name, "\\") {
+		return fmt.Errorf("invalid context path %q: must be a relative slash-separated path", name)
+	}
+	if path.Clean(name) != name || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("invalid context path %q: must not contain . or .. elements", name)
+	}
+	return nil
+}
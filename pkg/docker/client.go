@@ -1,11 +1,14 @@
 package docker
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -13,7 +16,8 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
-	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
 )
 
 // Client wraps the Docker client
@@ -71,11 +75,54 @@ func (c *Client) ListContainers(ctx context.Context, all bool) ([]types.Containe
 	})
 }
 
-// ExecCommand executes a command in a container
-func (c *Client) ExecCommand(ctx context.Context, containerID string, cmd string) (string, error) {
+// ExecOptions configures a command run in a container
+type ExecOptions struct {
+	Cmd        []string      // Command and arguments, run directly without a shell
+	User       string        // User (and optionally group) to run as
+	Env        []string      // Additional environment variables (KEY=VALUE)
+	WorkingDir string        // Working directory inside the container
+	Privileged bool          // Run with extended privileges
+	Stdin      []byte        // Content piped to standard input; nil leaves stdin detached
+	Timeout    time.Duration // Maximum time to collect output; zero waits for the command to exit
+}
+
+// ExecResult holds the output and exit status of a command run in a container
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int  // -1 when the command was still running after the timeout
+	TimedOut bool // The timeout expired and the output is partial
+	Killed   bool // The command was killed because the timeout expired; false when the container cannot run the kill
+}
+
+// execMarkerEnv is set on every exec to a value unique to the call, so the
+// processes it starts can be found inside the container and killed
+const execMarkerEnv = "DOCKER_MCP_EXEC"
+
+// killExecScript kills every process in the container whose environment
+// holds the marker passed as $1
+const killExecScript = `for p in /proc/[0-9]*; do tr '\0' '\n' < "$p/environ" 2>/dev/null | grep -qxF "$1" && kill -KILL "${p#/proc/}" 2>/dev/null; done; true`
+
+// killExecWait is how long to wait for a killed exec to be reported finished
+const killExecWait = 5 * time.Second
+
+// ExecCommand executes a command in a container. When the timeout expires
+// the output collected so far is returned and the command is killed; it is
+// also killed when ctx is cancelled.
+func (c *Client) ExecCommand(ctx context.Context, containerID string, opts ExecOptions) (ExecResult, error) {
+	marker, err := execMarker()
+	if err != nil {
+		return ExecResult{}, err
+	}
+
 	// Configure execution options
 	execConfig := container.ExecOptions{
-		Cmd:          []string{"sh", "-c", cmd},
+		Cmd:          opts.Cmd,
+		User:         opts.User,
+		Env:          append(append([]string(nil), opts.Env...), marker),
+		WorkingDir:   opts.WorkingDir,
+		Privileged:   opts.Privileged,
+		AttachStdin:  opts.Stdin != nil,
 		AttachStdout: true,
 		AttachStderr: true,
 	}
@@ -83,23 +130,138 @@ func (c *Client) ExecCommand(ctx context.Context, containerID string, cmd string
 	// Create exec instance
 	execID, err := c.dockerClient.ContainerExecCreate(ctx, containerID, execConfig)
 	if err != nil {
-		return "", fmt.Errorf("failed to create exec: %w", err)
+		return ExecResult{}, fmt.Errorf("failed to create exec: %w", err)
 	}
 
 	// Attach to the exec instance to get output
 	resp, err := c.dockerClient.ContainerExecAttach(ctx, execID.ID, container.ExecAttachOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to attach to exec: %w", err)
+		return ExecResult{}, fmt.Errorf("failed to attach to exec: %w", err)
 	}
 	defer resp.Close()
 
-	// Read all output from the command
-	output, err := io.ReadAll(resp.Reader)
+	// Pipe stdin and signal EOF so the command does not wait for more input
+	if opts.Stdin != nil {
+		go func() {
+			resp.Conn.Write(opts.Stdin)
+			resp.CloseWrite()
+		}()
+	}
+
+	// Without a TTY the output is multiplexed; split it into stdout and stderr
+	var stdout, stderr bytes.Buffer
+	copyDone := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(&stdout, &stderr, resp.Reader)
+		copyDone <- err
+	}()
+
+	var timeout <-chan time.Time
+	if opts.Timeout > 0 {
+		timer := time.NewTimer(opts.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	result := ExecResult{}
+	select {
+	case err := <-copyDone:
+		if err != nil {
+			return ExecResult{}, fmt.Errorf("failed to read output: %w", err)
+		}
+	case <-timeout:
+		// Closing the stream unblocks the copy; keep what was received
+		resp.Close()
+		<-copyDone
+		result.TimedOut = true
+		result.Killed = c.killExec(ctx, containerID, execID.ID, marker, opts.User)
+	case <-ctx.Done():
+		resp.Close()
+		<-copyDone
+		// The request is gone, but the command must not outlive it
+		killCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*killExecWait)
+		defer cancel()
+		c.killExec(killCtx, containerID, execID.ID, marker, opts.User)
+		return ExecResult{}, ctx.Err()
+	}
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	// Fetch the exit status once the output stream has ended
+	inspect, err := c.dockerClient.ContainerExecInspect(ctx, execID.ID)
 	if err != nil {
-		return "", fmt.Errorf("failed to read output: %w", err)
+		return ExecResult{}, fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	result.ExitCode = inspect.ExitCode
+	if inspect.Running {
+		result.ExitCode = -1
 	}
 
-	return string(output), nil
+	return result, nil
+}
+
+// execMarker returns a new execMarkerEnv variable
+func execMarker() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate exec marker: %w", err)
+	}
+	return execMarkerEnv + "=" + hex.EncodeToString(buf), nil
+}
+
+// killExec kills the processes of an exec that outlived its timeout and
+// waits for the daemon to report it finished. The Engine API cannot signal an
+// exec, and the Pid it reports is in the host's PID namespace, so a second
+// exec finds the processes by their marker through the container's /proc.
+// It runs as the same user so it may read their environment and kill them.
+// It reports false when the command could not be killed, such as in
+// distroless or scratch images that have no sh, tr or grep.
+func (c *Client) killExec(ctx context.Context, containerID, execID, marker, user string) bool {
+	inspect, err := c.dockerClient.ContainerExecInspect(ctx, execID)
+	if err != nil || !inspect.Running || inspect.Pid == 0 {
+		return false
+	}
+
+	killer, err := c.dockerClient.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          []string{"sh", "-c", killExecScript, "sh", marker},
+		User:         user,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return false
+	}
+	resp, err := c.dockerClient.ContainerExecAttach(ctx, killer.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, resp.Reader)
+	resp.Close()
+
+	// A container without sh, tr or grep fails to start the kill or exits non-zero
+	status, err := c.dockerClient.ContainerExecInspect(ctx, killer.ID)
+	if err != nil || status.ExitCode != 0 {
+		return false
+	}
+
+	deadline := time.Now().Add(killExecWait)
+	for {
+		inspect, err := c.dockerClient.ContainerExecInspect(ctx, execID)
+		if err != nil {
+			return false
+		}
+		if !inspect.Running {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-ctx.Done():
+			return false
+		}
+	}
 }
 
 // PullImage pulls a Docker image from registry
@@ -122,12 +284,15 @@ func (c *Client) SearchImages(ctx context.Context, term string, limit int) ([]re
 }
 
 // CreateContainer creates a new container
-func (c *Client) CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, name string) (container.CreateResponse, error) {
+func (c *Client) CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, name string) (container.CreateResponse, error) {
+	if networkingConfig == nil {
+		networkingConfig = &network.NetworkingConfig{}
+	}
 	return c.dockerClient.ContainerCreate(
 		ctx,
 		config,
 		hostConfig,
-		&network.NetworkingConfig{},
+		networkingConfig,
 		nil,
 		name,
 	)
@@ -148,6 +313,42 @@ func (c *Client) RestartContainer(ctx context.Context, containerID string, timeo
 	return c.dockerClient.ContainerRestart(ctx, containerID, container.StopOptions{Timeout: timeout})
 }
 
+// UpdateContainer changes the resource limits and restart policy of a container without recreating it
+func (c *Client) UpdateContainer(ctx context.Context, containerID string, update container.UpdateConfig) (container.UpdateResponse, error) {
+	return c.dockerClient.ContainerUpdate(ctx, containerID, update)
+}
+
+// PauseContainer suspends all processes in a container
+func (c *Client) PauseContainer(ctx context.Context, containerID string) error {
+	return c.dockerClient.ContainerPause(ctx, containerID)
+}
+
+// UnpauseContainer resumes the processes in a paused container
+func (c *Client) UnpauseContainer(ctx context.Context, containerID string) error {
+	return c.dockerClient.ContainerUnpause(ctx, containerID)
+}
+
+// KillContainer sends a signal to a container's main process
+func (c *Client) KillContainer(ctx context.Context, containerID, signal string) error {
+	return c.dockerClient.ContainerKill(ctx, containerID, signal)
+}
+
+// RenameContainer gives a container a new name
+func (c *Client) RenameContainer(ctx context.Context, containerID, newName string) error {
+	return c.dockerClient.ContainerRename(ctx, containerID, newName)
+}
+
+// WaitContainer blocks until the container meets the condition or ctx is done
+func (c *Client) WaitContainer(ctx context.Context, containerID string, condition container.WaitCondition) (container.WaitResponse, error) {
+	resultC, errC := c.dockerClient.ContainerWait(ctx, containerID, condition)
+	select {
+	case result := <-resultC:
+		return result, nil
+	case err := <-errC:
+		return container.WaitResponse{}, err
+	}
+}
+
 // RemoveContainer removes a container
 func (c *Client) RemoveContainer(ctx context.Context, containerID string, force, removeVolumes bool) error {
 	return c.dockerClient.ContainerRemove(ctx, containerID, container.RemoveOptions{
@@ -164,15 +365,7 @@ func (c *Client) RemoveImage(ctx context.Context, imageID string, force bool) ([
 }
 
 // ContainerLogs retrieves logs from a container
-func (c *Client) ContainerLogs(ctx context.Context, containerID string, follow, timestamps bool, tail string) (io.ReadCloser, error) {
-	// Configure log options
-	options := container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Follow:     follow,
-		Timestamps: timestamps,
-		Tail:       tail,
-	}
+func (c *Client) ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error) {
 	return c.dockerClient.ContainerLogs(ctx, containerID, options)
 }
 
@@ -181,35 +374,40 @@ func (c *Client) InspectContainer(ctx context.Context, containerID string) (type
 	return c.dockerClient.ContainerInspect(ctx, containerID)
 }
 
-// InspectImage retrieves detailed information about an image
-func (c *Client) InspectImage(ctx context.Context, imageID string) (types.ImageInspect, error) {
-	imageInfo, _, err := c.dockerClient.ImageInspectWithRaw(ctx, imageID)
-	return imageInfo, err
+// ContainerTop lists the processes running in a container, using ps with the given arguments
+func (c *Client) ContainerTop(ctx context.Context, containerID string, psArgs []string) (container.TopResponse, error) {
+	return c.dockerClient.ContainerTop(ctx, containerID, psArgs)
 }
 
-// BuildImage builds a Docker image from a Dockerfile and context
-func (c *Client) BuildImage(ctx context.Context, contextPath string, dockerfileName string, tags []string, noCache, pull bool) (types.ImageBuildResponse, error) {
-	// Verify that the Dockerfile exists in the context
-	dockerfilePath := filepath.Join(contextPath, dockerfileName)
-	if _, err := os.Stat(dockerfilePath); os.IsNotExist(err) {
-		return types.ImageBuildResponse{}, fmt.Errorf("dockerfile %s not found in context", dockerfileName)
-	}
+// ContainerDiff lists the filesystem changes made in a container since it was created from its image
+func (c *Client) ContainerDiff(ctx context.Context, containerID string) ([]container.FilesystemChange, error) {
+	return c.dockerClient.ContainerDiff(ctx, containerID)
+}
 
-	// Create build context from the directory
-	buildContext, err := archive.TarWithOptions(contextPath, &archive.TarOptions{})
+// ContainerPorts returns the host bindings of every port a container exposes or publishes.
+// Ports that are exposed but not bound, or whose container is not running, have no bindings.
+func (c *Client) ContainerPorts(ctx context.Context, containerID string) (nat.PortMap, error) {
+	info, err := c.dockerClient.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return types.ImageBuildResponse{}, fmt.Errorf("failed to create build context: %w", err)
+		return nil, err
 	}
 
-	// Configure build options
-	buildOptions := types.ImageBuildOptions{
-		Dockerfile: dockerfileName,
-		Tags:       tags,
-		NoCache:    noCache,
-		PullParent: pull,
-		Remove:     true,
+	ports := nat.PortMap{}
+	if info.Config != nil {
+		for port := range info.Config.ExposedPorts {
+			ports[port] = nil
+		}
+	}
+	if info.NetworkSettings != nil {
+		for port, bindings := range info.NetworkSettings.Ports {
+			ports[port] = bindings
+		}
 	}
+	return ports, nil
+}
 
-	// Execute the build
-	return c.dockerClient.ImageBuild(ctx, buildContext, buildOptions)
+// InspectImage retrieves detailed information about an image
+func (c *Client) InspectImage(ctx context.Context, imageID string) (types.ImageInspect, error) {
+	imageInfo, _, err := c.dockerClient.ImageInspectWithRaw(ctx, imageID)
+	return imageInfo, err
 }
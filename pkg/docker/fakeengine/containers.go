@@ -0,0 +1,822 @@
+package fakeengine
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+)
+
+// LogEntry is a single line of simulated container output
+type LogEntry struct {
+	Stream string    // "stdout" or "stderr"
+	Text   string    // Line content without the trailing newline
+	Time   time.Time // Time the line was written; zero means now
+}
+
+// fakeContainer is a container with its recorded output
+type fakeContainer struct {
+	container.InspectResponse
+	created time.Time
+	logs    []LogEntry
+	changes []container.FilesystemChange
+	signals []string // Signals sent with kill
+	exits   int      // Number of times the container has exited
+}
+
+// fakeExec is an exec instance created in a container
+type fakeExec struct {
+	id          string
+	containerID string
+	config      container.ExecOptions
+	running     bool
+	pid         int
+	exitCode    int
+}
+
+// RunningExecs returns the number of exec instances still running in a container
+func (e *Engine) RunningExecs(containerID string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(containerID)
+	if c == nil {
+		return 0
+	}
+	running := 0
+	for _, ex := range e.execs {
+		if ex.containerID == c.ID && ex.running {
+			running++
+		}
+	}
+	return running
+}
+
+// AddLogs appends output lines to a container's log
+func (e *Engine) AddLogs(containerID string, entries ...LogEntry) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(containerID)
+	if c == nil {
+		return fmt.Errorf("no such container: %s", containerID)
+	}
+	for _, entry := range entries {
+		if entry.Time.IsZero() {
+			entry.Time = time.Now()
+		}
+		c.logs = append(c.logs, entry)
+	}
+	return nil
+}
+
+// AddChanges records filesystem changes made in a container, as reported by diff
+func (e *Engine) AddChanges(containerID string, changes ...container.FilesystemChange) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(containerID)
+	if c == nil {
+		return fmt.Errorf("no such container: %s", containerID)
+	}
+	c.changes = append(c.changes, changes...)
+	return nil
+}
+
+// ContainerState returns the status of a container ("created", "running", "exited", ...)
+func (e *Engine) ContainerState(containerID string) (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(containerID)
+	if c == nil {
+		return "", false
+	}
+	return c.State.Status, true
+}
+
+// findContainer resolves a container by ID, name or unique ID prefix. Callers must hold e.mu.
+func (e *Engine) findContainer(idOrName string) *fakeContainer {
+	if c, ok := e.containers[idOrName]; ok {
+		return c
+	}
+
+	name := "/" + strings.TrimPrefix(idOrName, "/")
+	var match *fakeContainer
+	for id, c := range e.containers {
+		if c.Name == name {
+			return c
+		}
+		if strings.HasPrefix(id, idOrName) {
+			if match != nil {
+				return nil
+			}
+			match = c
+		}
+	}
+	return match
+}
+
+// summary converts a container to its list representation
+func (c *fakeContainer) summary() container.Summary {
+	s := container.Summary{
+		ID:      c.ID,
+		Names:   []string{c.Name},
+		Image:   c.Config.Image,
+		ImageID: c.Image,
+		Command: strings.Join(c.Config.Cmd, " "),
+		Created: c.created.Unix(),
+		Labels:  c.Config.Labels,
+		State:   c.State.Status,
+		Status:  c.State.Status,
+		NetworkSettings: &container.NetworkSettingsSummary{
+			Networks: c.NetworkSettings.Networks,
+		},
+	}
+	s.HostConfig.NetworkMode = string(c.HostConfig.NetworkMode)
+
+	for port, bindings := range c.HostConfig.PortBindings {
+		for _, b := range bindings {
+			public, _ := strconv.Atoi(b.HostPort)
+			s.Ports = append(s.Ports, container.Port{
+				IP:          b.HostIP,
+				PrivatePort: uint16(port.Int()),
+				PublicPort:  uint16(public),
+				Type:        port.Proto(),
+			})
+		}
+	}
+
+	s.Mounts = append(s.Mounts, c.Mounts...)
+
+	return s
+}
+
+// handleContainerList serves GET /containers/json
+func (e *Engine) handleContainerList(w http.ResponseWriter, r *http.Request) {
+	filterArgs, err := queryFilters(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	all := queryBool(r, "all")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := []container.Summary{}
+	for _, c := range e.containers {
+		if !all && !c.State.Running {
+			continue
+		}
+		if !filterArgs.MatchKVList("label", c.Config.Labels) {
+			continue
+		}
+		if filterArgs.Contains("name") && !filterArgs.Match("name", strings.TrimPrefix(c.Name, "/")) {
+			continue
+		}
+		if filterArgs.Contains("status") && !filterArgs.ExactMatch("status", c.State.Status) {
+			continue
+		}
+		result = append(result, c.summary())
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleContainerCreate serves POST /containers/create
+func (e *Engine) handleContainerCreate(w http.ResponseWriter, r *http.Request) {
+	var req container.CreateRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Config == nil || req.Config.Image == "" {
+		writeError(w, http.StatusBadRequest, "config.Image is required")
+		return
+	}
+	if req.HostConfig == nil {
+		req.HostConfig = &container.HostConfig{}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	img := e.findImage(req.Config.Image)
+	if img == nil {
+		writeError(w, http.StatusNotFound, "No such image: "+req.Config.Image)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if existing := e.findContainer(name); name != "" && existing != nil && existing.Name == "/"+name {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Conflict. The container name \"/%s\" is already in use", name))
+		return
+	}
+
+	id := newID()
+	if name == "" {
+		name = id[:12]
+	}
+
+	now := time.Now()
+	c := &fakeContainer{
+		InspectResponse: container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{
+				ID:         id,
+				Created:    now.Format(time.RFC3339Nano),
+				Name:       "/" + name,
+				Image:      img.ID,
+				State:      &container.State{Status: "created"},
+				HostConfig: req.HostConfig,
+			},
+			Config: req.Config,
+			NetworkSettings: &container.NetworkSettings{
+				Networks: map[string]*network.EndpointSettings{},
+			},
+		},
+		created: now,
+	}
+	if len(c.Config.Cmd) == 0 {
+		c.Config.Cmd = img.Config.Cmd
+	}
+	if c.Config.ExposedPorts == nil {
+		c.Config.ExposedPorts = nat.PortSet{}
+	}
+
+	// Record mounts, creating missing named and anonymous volumes
+	var mounts []mount.Mount
+	for _, bind := range req.HostConfig.Binds {
+		m, err := parseBind(bind)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mounts = append(mounts, m)
+	}
+	for _, m := range append(mounts, req.HostConfig.Mounts...) {
+		point := container.MountPoint{
+			Type:        m.Type,
+			Source:      m.Source,
+			Destination: m.Target,
+			RW:          !m.ReadOnly,
+		}
+		if m.Type == mount.TypeVolume {
+			v, ok := e.volumes[m.Source]
+			if !ok {
+				v = e.createVolume(volume.CreateOptions{Name: m.Source})
+			}
+			point.Name = v.Name
+			point.Source = v.Mountpoint
+			point.Driver = v.Driver
+		}
+		c.Mounts = append(c.Mounts, point)
+	}
+
+	// Attach networks: explicit endpoints, or the network named by the network mode
+	endpoints := map[string]*network.EndpointSettings{}
+	if req.NetworkingConfig != nil {
+		for netName, settings := range req.NetworkingConfig.EndpointsConfig {
+			if settings == nil {
+				settings = &network.EndpointSettings{}
+			}
+			endpoints[netName] = settings
+		}
+	}
+	if len(endpoints) == 0 {
+		mode := string(req.HostConfig.NetworkMode)
+		if mode == "" || mode == "default" {
+			mode = "bridge"
+		}
+		if !strings.HasPrefix(mode, "container:") {
+			endpoints[mode] = &network.EndpointSettings{}
+		}
+	}
+	for netName := range endpoints {
+		if e.findNetwork(netName) == nil {
+			writeError(w, http.StatusNotFound, "network "+netName+" not found")
+			return
+		}
+	}
+	for netName, settings := range endpoints {
+		e.attach(e.findNetwork(netName), c, settings)
+	}
+
+	e.containers[id] = c
+	writeJSON(w, http.StatusCreated, container.CreateResponse{ID: id, Warnings: []string{}})
+}
+
+// handleContainerInspect serves GET /containers/{id}/json
+func (e *Engine) handleContainerInspect(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, c.InspectResponse)
+}
+
+// handleContainerStart serves POST /containers/{id}/start
+func (e *Engine) handleContainerStart(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	if c.State.Running {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	c.setRunning()
+	e.notifyStateChange()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleContainerStop serves POST /containers/{id}/stop
+func (e *Engine) handleContainerStop(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	if !c.State.Running {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	c.setExited(0)
+	e.notifyStateChange()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleContainerRestart serves POST /containers/{id}/restart
+func (e *Engine) handleContainerRestart(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	if c.State.Running {
+		c.setExited(0)
+	}
+	c.RestartCount++
+	c.setRunning()
+	e.notifyStateChange()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleContainerRemove serves DELETE /containers/{id}
+func (e *Engine) handleContainerRemove(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	if c.State.Running && !queryBool(r, "force") {
+		writeError(w, http.StatusConflict, "cannot remove container \""+c.Name+"\": container is running: stop the container before removing or force remove")
+		return
+	}
+
+	if c.State.Running {
+		c.setExited(137)
+	}
+	for _, n := range e.networks {
+		delete(n.Containers, c.ID)
+	}
+	delete(e.containers, c.ID)
+	e.notifyStateChange()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleContainerLogs serves GET /containers/{id}/logs as a multiplexed stream
+func (e *Engine) handleContainerLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	e.mu.Lock()
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		e.mu.Unlock()
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	tty := c.Config.Tty
+	entries := append([]LogEntry(nil), c.logs...)
+	e.mu.Unlock()
+
+	since, err := parseUnixTime(query.Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	until, err := parseUnixTime(query.Get("until"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matches := func(entry LogEntry) bool {
+		if entry.Stream == "stdout" && !queryBool(r, "stdout") {
+			return false
+		}
+		if entry.Stream == "stderr" && !queryBool(r, "stderr") {
+			return false
+		}
+		if !since.IsZero() && entry.Time.Before(since) {
+			return false
+		}
+		if !until.IsZero() && entry.Time.After(until) {
+			return false
+		}
+		return true
+	}
+
+	var selected []LogEntry
+	for _, entry := range entries {
+		if matches(entry) {
+			selected = append(selected, entry)
+		}
+	}
+
+	if tail := query.Get("tail"); tail != "" && tail != "all" {
+		n, err := strconv.Atoi(tail)
+		if err == nil && n >= 0 && n < len(selected) {
+			selected = selected[len(selected)-n:]
+		}
+	}
+
+	if tty {
+		w.Header().Set("Content-Type", "application/vnd.docker.raw-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+	}
+	w.WriteHeader(http.StatusOK)
+
+	stdout, stderr := io.Writer(w), io.Writer(w)
+	if !tty {
+		stdout = stdcopy.NewStdWriter(w, stdcopy.Stdout)
+		stderr = stdcopy.NewStdWriter(w, stdcopy.Stderr)
+	}
+	write := func(entries []LogEntry) {
+		for _, entry := range entries {
+			line := entry.Text + "\n"
+			if queryBool(r, "timestamps") {
+				line = entry.Time.UTC().Format(time.RFC3339Nano) + " " + line
+			}
+			if entry.Stream == "stderr" {
+				stderr.Write([]byte(line))
+			} else {
+				stdout.Write([]byte(line))
+			}
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+	write(selected)
+
+	if !queryBool(r, "follow") {
+		return
+	}
+
+	// Follow: stream lines added later until the container stops or the client goes away
+	sent := len(entries)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		e.mu.Lock()
+		running := e.containers[c.ID] == c && c.State.Running
+		added := append([]LogEntry(nil), c.logs[sent:]...)
+		sent = len(c.logs)
+		e.mu.Unlock()
+
+		var matched []LogEntry
+		for _, entry := range added {
+			if matches(entry) {
+				matched = append(matched, entry)
+			}
+		}
+		write(matched)
+
+		if !running {
+			return
+		}
+	}
+}
+
+// handleContainerTop serves GET /containers/{id}/top
+func (e *Engine) handleContainerTop(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	c := e.findContainer(r.PathValue("id"))
+	var (
+		id      string
+		running bool
+		cmd     []string
+	)
+	if c != nil {
+		id = c.ID
+		running = c.State.Running
+		cmd = c.Config.Cmd
+	}
+	handler := e.TopHandler
+	e.mu.Unlock()
+
+	switch {
+	case c == nil:
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	case !running:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is not running", id))
+		return
+	}
+
+	psArgs := r.URL.Query().Get("ps_args")
+	if handler != nil {
+		writeJSON(w, http.StatusOK, handler(id, psArgs))
+		return
+	}
+	writeJSON(w, http.StatusOK, container.TopResponse{
+		Titles:    []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"},
+		Processes: [][]string{{"root", "4242", "4221", "0", "12:00", "?", "00:00:00", strings.Join(cmd, " ")}},
+	})
+}
+
+// handleContainerChanges serves GET /containers/{id}/changes
+func (e *Engine) handleContainerChanges(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, c.changes)
+}
+
+// handleExecCreate serves POST /containers/{id}/exec
+func (e *Engine) handleExecCreate(w http.ResponseWriter, r *http.Request) {
+	var config container.ExecOptions
+	if err := decodeBody(r, &config); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	if !c.State.Running {
+		writeError(w, http.StatusConflict, "container "+c.ID+" is not running")
+		return
+	}
+	if len(config.Cmd) == 0 {
+		writeError(w, http.StatusBadRequest, "No exec command specified")
+		return
+	}
+
+	ex := &fakeExec{id: newID(), containerID: c.ID, config: config}
+	e.execs[ex.id] = ex
+	writeJSON(w, http.StatusCreated, map[string]string{"Id": ex.id})
+}
+
+// handleExecStart serves POST /exec/{id}/start by hijacking the connection
+// and writing the simulated command output as a multiplexed stream
+func (e *Engine) handleExecStart(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	ex, ok := e.execs[r.PathValue("id")]
+	if ok {
+		e.lastPid++
+		ex.running = true
+		ex.pid = e.lastPid
+	}
+	handler := e.ExecHandler
+	e.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such exec instance: "+r.PathValue("id"))
+		return
+	}
+
+	var opts container.ExecStartOptions
+	if err := decodeBody(r, &opts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "connection cannot be hijacked")
+		return
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	contentType := "application/vnd.docker.multiplexed-stream"
+	if ex.config.Tty {
+		contentType = "application/vnd.docker.raw-stream"
+	}
+	fmt.Fprintf(buf, "HTTP/1.1 101 UPGRADED\r\nContent-Type: %s\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n", contentType)
+	buf.Flush()
+
+	// Stdin is read until the client closes its write side
+	var stdin []byte
+	if ex.config.AttachStdin {
+		stdin, _ = io.ReadAll(buf)
+	}
+
+	stdout, stderr := io.Writer(io.Discard), io.Writer(io.Discard)
+	switch {
+	case ex.config.Tty:
+		stdout, stderr = conn, conn
+	default:
+		if ex.config.AttachStdout {
+			stdout = stdcopy.NewStdWriter(conn, stdcopy.Stdout)
+		}
+		if ex.config.AttachStderr {
+			stderr = stdcopy.NewStdWriter(conn, stdcopy.Stderr)
+		}
+	}
+
+	req := &ExecRequest{
+		ContainerID: ex.containerID,
+		Config:      ex.config,
+		Stdin:       stdin,
+		Stdout:      stdout,
+		Stderr:      stderr,
+	}
+	e.mu.Lock()
+	e.lastExec = req
+	e.mu.Unlock()
+
+	if handler == nil {
+		handler = defaultExecHandler
+	}
+	result := handler(req)
+
+	if result.Stdout != "" {
+		io.WriteString(stdout, result.Stdout)
+	}
+	if result.Stderr != "" {
+		io.WriteString(stderr, result.Stderr)
+	}
+
+	e.mu.Lock()
+	ex.running = false
+	ex.exitCode = result.ExitCode
+	e.mu.Unlock()
+}
+
+// handleExecInspect serves GET /exec/{id}/json
+func (e *Engine) handleExecInspect(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ex, ok := e.execs[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "No such exec instance: "+r.PathValue("id"))
+		return
+	}
+	inspect := container.ExecInspect{
+		ExecID:      ex.id,
+		ContainerID: ex.containerID,
+		Running:     ex.running,
+		ExitCode:    ex.exitCode,
+	}
+	if ex.running {
+		inspect.Pid = ex.pid
+	}
+	writeJSON(w, http.StatusOK, inspect)
+}
+
+// defaultExecHandler echoes the command line
+func defaultExecHandler(req *ExecRequest) ExecResult {
+	return ExecResult{Stdout: strings.Join(req.Config.Cmd, " ") + "\n"}
+}
+
+// setRunning marks the container as running
+func (c *fakeContainer) setRunning() {
+	c.State.Status = "running"
+	c.State.Running = true
+	c.State.Paused = false
+	c.State.Pid = 4242
+	c.State.ExitCode = 0
+	c.State.StartedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	c.publishPorts()
+}
+
+// publishPorts resolves the container's port bindings as the daemon does on
+// start. A binding without a host port is given 32768 plus the container port.
+func (c *fakeContainer) publishPorts() {
+	ports := nat.PortMap{}
+	for port := range c.Config.ExposedPorts {
+		ports[port] = nil
+	}
+	for port, bindings := range c.HostConfig.PortBindings {
+		resolved := make([]nat.PortBinding, 0, len(bindings))
+		for _, b := range bindings {
+			if b.HostIP == "" {
+				b.HostIP = "0.0.0.0"
+			}
+			if b.HostPort == "" {
+				b.HostPort = strconv.Itoa(32768 + port.Int())
+			}
+			resolved = append(resolved, b)
+		}
+		ports[port] = resolved
+	}
+	c.NetworkSettings.Ports = ports
+}
+
+// setExited marks the container as exited with the given code
+func (c *fakeContainer) setExited(code int) {
+	c.State.Status = "exited"
+	c.State.Running = false
+	c.State.Paused = false
+	c.State.Pid = 0
+	c.State.ExitCode = code
+	c.exits++
+	c.State.FinishedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	c.NetworkSettings.Ports = nat.PortMap{}
+}
+
+// parseBind converts a "source:target[:options]" bind the way the daemon does:
+// absolute sources are host paths, other sources name a volume
+func parseBind(bind string) (mount.Mount, error) {
+	parts := strings.SplitN(bind, ":", 3)
+	if len(parts) == 1 {
+		return mount.Mount{Type: mount.TypeVolume, Target: bind}, nil
+	}
+	if parts[0] == "" || !strings.HasPrefix(parts[1], "/") {
+		return mount.Mount{}, fmt.Errorf("invalid volume specification: '%s'", bind)
+	}
+
+	m := mount.Mount{Type: mount.TypeVolume, Source: parts[0], Target: parts[1]}
+	if strings.HasPrefix(parts[0], "/") {
+		m.Type = mount.TypeBind
+	}
+	if len(parts) == 3 {
+		for _, opt := range strings.Split(parts[2], ",") {
+			if opt == "ro" {
+				m.ReadOnly = true
+			}
+		}
+	}
+	return m, nil
+}
+
+// parseUnixTime parses the "seconds[.nanoseconds]" timestamps sent by the client
+func parseUnixTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	secStr, nsecStr, _ := strings.Cut(value, ".")
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+	}
+	var nsec int64
+	if nsecStr != "" {
+		nsecStr = (nsecStr + "000000000")[:9]
+		nsec, _ = strconv.ParseInt(nsecStr, 10, 64)
+	}
+	if sec == 0 && nsec == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(sec, nsec), nil
+}
@@ -0,0 +1,286 @@
+// Package fakeengine provides an in-process fake of the Docker Engine API.
+//
+// The fake keeps containers, images, networks, volumes and exec instances in
+// memory and speaks enough of the HTTP API for docker.Client, and therefore
+// every MCP tool, to be exercised end-to-end without a Docker daemon:
+//
+//	engine := fakeengine.New()
+//	defer engine.Close()
+//	client, _ := docker.NewClient(engine.Host())
+package fakeengine
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// APIVersion is the Engine API version advertised by the fake
+const APIVersion = "1.47"
+
+// versionPrefix matches the "/v1.xx" prefix the client adds to every path
+var versionPrefix = regexp.MustCompile(`^/v[0-9]+\.[0-9]+/`)
+
+// ExecRequest describes a command started with exec
+type ExecRequest struct {
+	ContainerID string
+	Config      container.ExecOptions
+	Stdin       []byte // Content sent by the client before closing its write side
+
+	// Stdout and Stderr stream output to the client while the command runs
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ExecResult is the simulated outcome of a command run with exec.
+// Stdout and Stderr are sent after anything written through the ExecRequest writers.
+type ExecResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// BuildRequest records an image build received by the fake
+type BuildRequest struct {
+	Query map[string][]string // Build options sent as query parameters
+	Files map[string][]byte   // Regular files in the uploaded build context, keyed by path
+}
+
+// Engine is a fake Docker Engine API server
+type Engine struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	containers map[string]*fakeContainer
+	images     map[string]*fakeImage
+	networks   map[string]*network.Inspect
+	volumes    map[string]*fakeVolume
+	execs      map[string]*fakeExec
+	lastBuild  *BuildRequest
+	lastExec   *ExecRequest
+	lastPid    int // Host PID given to the last started exec
+
+	// stateChanged is closed and replaced whenever a container changes state
+	stateChanged chan struct{}
+
+	// ExecHandler simulates commands run with exec.
+	// The default echoes the command line to stdout and exits with 0.
+	ExecHandler func(req *ExecRequest) ExecResult
+
+	// PullHandler returns the progress messages streamed for an image pull.
+	// The default reports a single-layer download and registers the image.
+	PullHandler func(ref string) []jsonmessage.JSONMessage
+
+	// BuildHandler returns the messages streamed for an image build.
+	// The default reports a successful legacy build and registers the image.
+	BuildHandler func(req *BuildRequest) []jsonmessage.JSONMessage
+
+	// StatsHandler returns a resource usage sample for a running container.
+	// The default reports steady usage accumulated since the container started.
+	StatsHandler func(containerID string) container.StatsResponse
+
+	// TopHandler returns the process list of a running container for the given ps arguments.
+	// The default reports the container's command as a single process in ps -ef format.
+	TopHandler func(containerID, psArgs string) container.TopResponse
+
+	// SearchResults are returned by image search, filtered by the search term
+	SearchResults []registry.SearchResult
+}
+
+// New starts a fake engine with the default bridge, host and none networks
+func New() *Engine {
+	e := &Engine{
+		containers: make(map[string]*fakeContainer),
+		images:     make(map[string]*fakeImage),
+		networks:   make(map[string]*network.Inspect),
+		volumes:    make(map[string]*fakeVolume),
+		execs:      make(map[string]*fakeExec),
+		lastPid:    4000,
+
+		stateChanged: make(chan struct{}),
+		SearchResults: []registry.SearchResult{
+			{Name: "nginx", Description: "Official build of Nginx.", IsOfficial: true, StarCount: 20000},
+			{Name: "redis", Description: "Redis is an open source key-value store.", IsOfficial: true, StarCount: 13000},
+		},
+	}
+
+	for _, name := range []string{"bridge", "host", "none"} {
+		driver := name
+		if name == "none" {
+			driver = "null"
+		}
+		e.networks[name] = &network.Inspect{
+			Name:       name,
+			ID:         newID(),
+			Driver:     driver,
+			Scope:      "local",
+			Containers: map[string]network.EndpointResource{},
+		}
+	}
+
+	e.server = httptest.NewServer(e.routes())
+	return e
+}
+
+// Host returns the daemon address to pass to docker.NewClient
+func (e *Engine) Host() string {
+	return "tcp://" + e.server.Listener.Addr().String()
+}
+
+// Close shuts down the fake engine
+func (e *Engine) Close() {
+	e.server.CloseClientConnections()
+	e.server.Close()
+}
+
+// LastBuild returns the most recent build request, or nil if there was none
+func (e *Engine) LastBuild() *BuildRequest {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastBuild
+}
+
+// LastExec returns the most recent exec request, or nil if there was none
+func (e *Engine) LastExec() *ExecRequest {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastExec
+}
+
+// routes builds the HTTP handler serving the Engine API endpoints
+func (e *Engine) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /_ping", e.handlePing)
+	mux.HandleFunc("HEAD /_ping", e.handlePing)
+
+	// Containers
+	mux.HandleFunc("GET /containers/json", e.handleContainerList)
+	mux.HandleFunc("POST /containers/create", e.handleContainerCreate)
+	mux.HandleFunc("GET /containers/{id}/json", e.handleContainerInspect)
+	mux.HandleFunc("POST /containers/{id}/start", e.handleContainerStart)
+	mux.HandleFunc("POST /containers/{id}/stop", e.handleContainerStop)
+	mux.HandleFunc("POST /containers/{id}/restart", e.handleContainerRestart)
+	mux.HandleFunc("DELETE /containers/{id}", e.handleContainerRemove)
+	mux.HandleFunc("GET /containers/{id}/logs", e.handleContainerLogs)
+	mux.HandleFunc("GET /containers/{id}/stats", e.handleContainerStats)
+	mux.HandleFunc("GET /containers/{id}/top", e.handleContainerTop)
+	mux.HandleFunc("GET /containers/{id}/changes", e.handleContainerChanges)
+	mux.HandleFunc("POST /containers/{id}/update", e.handleContainerUpdate)
+	mux.HandleFunc("POST /containers/{id}/pause", e.handleContainerPause)
+	mux.HandleFunc("POST /containers/{id}/unpause", e.handleContainerUnpause)
+	mux.HandleFunc("POST /containers/{id}/kill", e.handleContainerKill)
+	mux.HandleFunc("POST /containers/{id}/rename", e.handleContainerRename)
+	mux.HandleFunc("POST /containers/{id}/wait", e.handleContainerWait)
+
+	// Exec
+	mux.HandleFunc("POST /containers/{id}/exec", e.handleExecCreate)
+	mux.HandleFunc("POST /exec/{id}/start", e.handleExecStart)
+	mux.HandleFunc("GET /exec/{id}/json", e.handleExecInspect)
+
+	// Images
+	mux.HandleFunc("GET /images/json", e.handleImageList)
+	mux.HandleFunc("GET /images/search", e.handleImageSearch)
+	mux.HandleFunc("POST /images/create", e.handleImagePull)
+	mux.HandleFunc("GET /images/{name...}", e.handleImageInspect)
+	mux.HandleFunc("DELETE /images/{name...}", e.handleImageRemove)
+	mux.HandleFunc("POST /build", e.handleBuild)
+
+	// Networks
+	mux.HandleFunc("GET /networks", e.handleNetworkList)
+	mux.HandleFunc("POST /networks/create", e.handleNetworkCreate)
+	mux.HandleFunc("POST /networks/prune", e.handleNetworkPrune)
+	mux.HandleFunc("GET /networks/{id}", e.handleNetworkInspect)
+	mux.HandleFunc("DELETE /networks/{id}", e.handleNetworkRemove)
+	mux.HandleFunc("POST /networks/{id}/connect", e.handleNetworkConnect)
+	mux.HandleFunc("POST /networks/{id}/disconnect", e.handleNetworkDisconnect)
+
+	// Volumes
+	mux.HandleFunc("GET /volumes", e.handleVolumeList)
+	mux.HandleFunc("POST /volumes/create", e.handleVolumeCreate)
+	mux.HandleFunc("POST /volumes/prune", e.handleVolumePrune)
+	mux.HandleFunc("GET /volumes/{name}", e.handleVolumeInspect)
+	mux.HandleFunc("DELETE /volumes/{name}", e.handleVolumeRemove)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Strip the API version prefix so routes match every negotiated version
+		if loc := versionPrefix.FindStringIndex(r.URL.Path); loc != nil {
+			r.URL.Path = "/" + r.URL.Path[loc[1]:]
+			r.URL.RawPath = ""
+		}
+		w.Header().Set("Api-Version", APIVersion)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// handlePing answers API version negotiation
+func (e *Engine) handlePing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("OSType", "linux")
+	w.Header().Set("Docker-Experimental", "false")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write([]byte("OK"))
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an Engine API error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+// decodeBody decodes a JSON request body into v
+func decodeBody(r *http.Request, v interface{}) error {
+	if r.Body == nil {
+		return nil
+	}
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil && err.Error() == "EOF" {
+		return nil
+	}
+	return err
+}
+
+// queryFilters parses the "filters" query parameter
+func queryFilters(r *http.Request) (filters.Args, error) {
+	return filters.FromJSON(r.URL.Query().Get("filters"))
+}
+
+// queryBool reports whether a boolean query parameter is set to a true value
+func queryBool(r *http.Request, key string) bool {
+	v := r.URL.Query().Get(key)
+	return v == "1" || strings.EqualFold(v, "true")
+}
+
+// newID generates a random 64 character hex identifier
+func newID() string {
+	buf := make([]byte, 32)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// fakeVolume is a volume with bookkeeping for anonymous volumes
+type fakeVolume struct {
+	volume.Volume
+	anonymous bool
+}
@@ -0,0 +1,403 @@
+package fakeengine
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// fakeImage is a locally stored image
+type fakeImage struct {
+	image.InspectResponse
+	created time.Time
+}
+
+// AddImage registers a local image with the given references and returns its ID
+func (e *Engine) AddImage(refs ...string) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.addImage("sha256:"+newID(), refs)
+}
+
+// addImage stores an image, moving any of refs already used by another image. Callers must hold e.mu.
+func (e *Engine) addImage(id string, refs []string) string {
+	img, ok := e.images[id]
+	if !ok {
+		now := time.Now()
+		img = &fakeImage{
+			InspectResponse: image.InspectResponse{
+				ID:           id,
+				RepoTags:     []string{},
+				Created:      now.UTC().Format(time.RFC3339Nano),
+				Architecture: "amd64",
+				Os:           "linux",
+				Size:         7 << 20,
+				Config: &container.Config{
+					Cmd: []string{"sh"},
+				},
+			},
+			created: now,
+		}
+		e.images[id] = img
+	}
+
+	for _, ref := range refs {
+		ref = normalizeRef(ref)
+		for _, other := range e.images {
+			other.RepoTags = removeString(other.RepoTags, ref)
+		}
+		img.RepoTags = append(img.RepoTags, ref)
+	}
+	return id
+}
+
+// findImage resolves an image by reference, ID or unique ID prefix. Callers must hold e.mu.
+func (e *Engine) findImage(ref string) *fakeImage {
+	normalized := normalizeRef(ref)
+	for _, img := range e.images {
+		for _, tag := range img.RepoTags {
+			if tag == normalized {
+				return img
+			}
+		}
+	}
+
+	prefix := ref
+	if !strings.HasPrefix(prefix, "sha256:") {
+		prefix = "sha256:" + prefix
+	}
+	var match *fakeImage
+	for id, img := range e.images {
+		if strings.HasPrefix(id, prefix) {
+			if match != nil {
+				return nil
+			}
+			match = img
+		}
+	}
+	return match
+}
+
+// imageInUse reports whether any container was created from the image. Callers must hold e.mu.
+func (e *Engine) imageInUse(id string) bool {
+	for _, c := range e.containers {
+		if c.Image == id {
+			return true
+		}
+	}
+	return false
+}
+
+// imageInUseByRunning reports whether a running container was created from the image. Callers must hold e.mu.
+func (e *Engine) imageInUseByRunning(id string) bool {
+	for _, c := range e.containers {
+		if c.Image == id && c.State.Running {
+			return true
+		}
+	}
+	return false
+}
+
+// handleImageList serves GET /images/json
+func (e *Engine) handleImageList(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := []image.Summary{}
+	for _, img := range e.images {
+		var containers int64
+		for _, c := range e.containers {
+			if c.Image == img.ID {
+				containers++
+			}
+		}
+		result = append(result, image.Summary{
+			ID:          img.ID,
+			RepoTags:    img.RepoTags,
+			RepoDigests: []string{},
+			Created:     img.created.Unix(),
+			Size:        img.Size,
+			Containers:  containers,
+			Labels:      map[string]string{},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleImageSearch serves GET /images/search
+func (e *Engine) handleImageSearch(w http.ResponseWriter, r *http.Request) {
+	term := r.URL.Query().Get("term")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := []registry.SearchResult{}
+	for _, res := range e.SearchResults {
+		if strings.Contains(res.Name, term) || strings.Contains(res.Description, term) {
+			result = append(result, res)
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleImagePull serves POST /images/create as a JSON message stream
+func (e *Engine) handleImagePull(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	ref := query.Get("fromImage")
+	if tag := query.Get("tag"); tag != "" {
+		if strings.HasPrefix(tag, "sha256:") {
+			ref += "@" + tag
+		} else {
+			ref += ":" + tag
+		}
+	}
+	ref = normalizeRef(ref)
+
+	e.mu.Lock()
+	handler := e.PullHandler
+	e.mu.Unlock()
+
+	var messages []jsonmessage.JSONMessage
+	if handler != nil {
+		messages = handler(ref)
+	} else {
+		messages = defaultPullMessages(ref)
+	}
+
+	if !hasError(messages) {
+		e.mu.Lock()
+		e.addImage("sha256:"+newID(), []string{ref})
+		e.mu.Unlock()
+	}
+
+	writeMessages(w, messages)
+}
+
+// handleImageInspect serves GET /images/{name}/json
+func (e *Engine) handleImageInspect(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("name"), "/json")
+	if !ok {
+		writeError(w, http.StatusNotFound, "page not found")
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	img := e.findImage(name)
+	if img == nil {
+		writeError(w, http.StatusNotFound, "No such image: "+name)
+		return
+	}
+	writeJSON(w, http.StatusOK, img.InspectResponse)
+}
+
+// handleImageRemove serves DELETE /images/{name}
+func (e *Engine) handleImageRemove(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	img := e.findImage(name)
+	if img == nil {
+		writeError(w, http.StatusNotFound, "No such image: "+name)
+		return
+	}
+
+	// Removing one of several tags only untags the image
+	ref := normalizeRef(name)
+	if len(img.RepoTags) > 1 && containsString(img.RepoTags, ref) {
+		img.RepoTags = removeString(img.RepoTags, ref)
+		writeJSON(w, http.StatusOK, []image.DeleteResponse{{Untagged: ref}})
+		return
+	}
+
+	if e.imageInUseByRunning(img.ID) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("conflict: unable to delete %s (cannot be forced) - image is being used by running container", shortID(img.ID)))
+		return
+	}
+	if e.imageInUse(img.ID) && !queryBool(r, "force") {
+		writeError(w, http.StatusConflict, fmt.Sprintf("conflict: unable to delete %s (must be forced) - image is being used by a container", shortID(img.ID)))
+		return
+	}
+
+	result := []image.DeleteResponse{}
+	for _, tag := range img.RepoTags {
+		result = append(result, image.DeleteResponse{Untagged: tag})
+	}
+	result = append(result, image.DeleteResponse{Deleted: img.ID})
+	delete(e.images, img.ID)
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleBuild serves POST /build, recording the uploaded context
+func (e *Engine) handleBuild(w http.ResponseWriter, r *http.Request) {
+	req := &BuildRequest{
+		Query: r.URL.Query(),
+		Files: map[string][]byte{},
+	}
+
+	tr := tar.NewReader(r.Body)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid build context: "+err.Error())
+			return
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid build context: "+err.Error())
+			return
+		}
+		req.Files[hdr.Name] = data
+	}
+
+	e.mu.Lock()
+	e.lastBuild = req
+	handler := e.BuildHandler
+	e.mu.Unlock()
+
+	var messages []jsonmessage.JSONMessage
+	if handler != nil {
+		messages = handler(req)
+	} else {
+		messages = defaultBuildMessages(req)
+	}
+
+	// Register the built image under the ID reported in the aux message
+	if !hasError(messages) {
+		for _, msg := range messages {
+			if msg.Aux == nil {
+				continue
+			}
+			var aux struct {
+				ID string
+			}
+			if err := json.Unmarshal(*msg.Aux, &aux); err == nil && aux.ID != "" {
+				e.mu.Lock()
+				e.addImage(aux.ID, req.Query["t"])
+				e.mu.Unlock()
+			}
+		}
+	}
+
+	writeMessages(w, messages)
+}
+
+// defaultPullMessages reports a single-layer download of ref
+func defaultPullMessages(ref string) []jsonmessage.JSONMessage {
+	layer := newID()[:12]
+	tag := ref[strings.LastIndex(ref, ":")+1:]
+	return []jsonmessage.JSONMessage{
+		{Status: "Pulling from " + strings.TrimSuffix(ref, ":"+tag), ID: tag},
+		{Status: "Pulling fs layer", ID: layer},
+		{Status: "Downloading", ID: layer, Progress: &jsonmessage.JSONProgress{Current: 1 << 20, Total: 2 << 20}},
+		{Status: "Downloading", ID: layer, Progress: &jsonmessage.JSONProgress{Current: 2 << 20, Total: 2 << 20}},
+		{Status: "Download complete", ID: layer},
+		{Status: "Pull complete", ID: layer},
+		{Status: "Digest: sha256:" + newID()},
+		{Status: "Status: Downloaded newer image for " + ref},
+	}
+}
+
+// defaultBuildMessages reports a successful single-step legacy build
+func defaultBuildMessages(req *BuildRequest) []jsonmessage.JSONMessage {
+	id := "sha256:" + newID()
+	aux := json.RawMessage(fmt.Sprintf(`{"ID":%q}`, id))
+
+	messages := []jsonmessage.JSONMessage{
+		{Stream: "Step 1/1 : FROM scratch\n"},
+		{Stream: " ---> " + shortID(id) + "\n"},
+		{Aux: &aux},
+		{Stream: "Successfully built " + shortID(id) + "\n"},
+	}
+	for _, tag := range req.Query["t"] {
+		messages = append(messages, jsonmessage.JSONMessage{Stream: "Successfully tagged " + normalizeRef(tag) + "\n"})
+	}
+	return messages
+}
+
+// writeMessages streams messages as newline-delimited JSON
+func writeMessages(w http.ResponseWriter, messages []jsonmessage.JSONMessage) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	for _, msg := range messages {
+		enc.Encode(msg)
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+}
+
+// hasError reports whether any message carries an error
+func hasError(messages []jsonmessage.JSONMessage) bool {
+	for _, msg := range messages {
+		if msg.Error != nil || msg.ErrorMessage != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRef strips the default registry and adds the latest tag when none is given
+func normalizeRef(ref string) string {
+	ref = strings.TrimPrefix(ref, "docker.io/")
+	ref = strings.TrimPrefix(ref, "library/")
+	if strings.Contains(ref, "@") {
+		return ref
+	}
+	if !strings.Contains(ref[strings.LastIndex(ref, "/")+1:], ":") {
+		ref += ":latest"
+	}
+	return ref
+}
+
+// shortID returns the 12 character form of an ID
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// removeString returns list without any occurrence of s
+func removeString(list []string, s string) []string {
+	result := list[:0]
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,305 @@
+package fakeengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// signalNumbers maps the signals accepted by kill to their Linux numbers
+var signalNumbers = map[string]int{
+	"SIGHUP": 1, "SIGINT": 2, "SIGQUIT": 3, "SIGILL": 4, "SIGTRAP": 5, "SIGABRT": 6, "SIGBUS": 7,
+	"SIGFPE": 8, "SIGKILL": 9, "SIGUSR1": 10, "SIGSEGV": 11, "SIGUSR2": 12, "SIGPIPE": 13,
+	"SIGALRM": 14, "SIGTERM": 15, "SIGCHLD": 17, "SIGCONT": 18, "SIGSTOP": 19, "SIGTSTP": 20,
+	"SIGTTIN": 21, "SIGTTOU": 22, "SIGURG": 23, "SIGXCPU": 24, "SIGXFSZ": 25, "SIGVTALRM": 26,
+	"SIGPROF": 27, "SIGWINCH": 28, "SIGIO": 29, "SIGPWR": 30, "SIGSYS": 31,
+}
+
+// handledSignals are the signals the simulated processes handle without exiting
+var handledSignals = map[string]bool{
+	"SIGHUP": true, "SIGUSR1": true, "SIGUSR2": true, "SIGWINCH": true, "SIGCHLD": true, "SIGCONT": true, "SIGURG": true,
+}
+
+// Signals returns the signals sent to a container with kill, in order
+func (e *Engine) Signals(containerID string) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(containerID)
+	if c == nil {
+		return nil
+	}
+	return append([]string(nil), c.signals...)
+}
+
+// notifyStateChange wakes every pending wait request. Callers must hold e.mu.
+func (e *Engine) notifyStateChange() {
+	close(e.stateChanged)
+	e.stateChanged = make(chan struct{})
+}
+
+// handleContainerPause serves POST /containers/{id}/pause
+func (e *Engine) handleContainerPause(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	switch {
+	case c == nil:
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	case !c.State.Running:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is not running", c.ID))
+		return
+	case c.State.Paused:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is already paused", c.ID))
+		return
+	}
+	c.State.Paused = true
+	c.State.Status = "paused"
+	e.notifyStateChange()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleContainerUnpause serves POST /containers/{id}/unpause
+func (e *Engine) handleContainerUnpause(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	switch {
+	case c == nil:
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	case !c.State.Paused:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Container %s is not paused", c.ID))
+		return
+	}
+	c.State.Paused = false
+	c.State.Status = "running"
+	e.notifyStateChange()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleContainerKill serves POST /containers/{id}/kill. Handled signals
+// such as SIGHUP leave the container running; any other signal makes it
+// exit with 128 plus the signal number.
+func (e *Engine) handleContainerKill(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+
+	signal := r.URL.Query().Get("signal")
+	if signal == "" {
+		signal = "SIGKILL"
+	}
+	name, number, ok := parseSignal(signal)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Invalid signal: "+signal)
+		return
+	}
+	if !c.State.Running {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Cannot kill container: %s: Container %s is not running", r.PathValue("id"), c.ID))
+		return
+	}
+
+	c.signals = append(c.signals, name)
+	if !handledSignals[name] {
+		c.setExited(128 + number)
+	}
+	e.notifyStateChange()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseSignal resolves a signal given by name, with or without the SIG
+// prefix, or by number
+func parseSignal(signal string) (string, int, bool) {
+	if n, err := strconv.Atoi(signal); err == nil {
+		for name, number := range signalNumbers {
+			if number == n {
+				return name, number, true
+			}
+		}
+		return "", 0, false
+	}
+	name := strings.ToUpper(signal)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	number, ok := signalNumbers[name]
+	return name, number, ok
+}
+
+// handleContainerRename serves POST /containers/{id}/rename
+func (e *Engine) handleContainerRename(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Query().Get("name"), "/")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "Neither old nor new names may be empty")
+		return
+	}
+	if existing := e.findContainer(name); existing != nil && existing.Name == "/"+name {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Conflict. The container name \"/%s\" is already in use by container %q", name, existing.ID))
+		return
+	}
+
+	c.Name = "/" + name
+	for _, n := range e.networks {
+		if endpoint, ok := n.Containers[c.ID]; ok {
+			endpoint.Name = name
+			n.Containers[c.ID] = endpoint
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleContainerWait serves POST /containers/{id}/wait. Like the daemon,
+// it sends the response headers at once and the body when the condition is met.
+func (e *Engine) handleContainerWait(w http.ResponseWriter, r *http.Request) {
+	condition := container.WaitCondition(r.URL.Query().Get("condition"))
+	switch condition {
+	case "":
+		condition = container.WaitConditionNotRunning
+	case container.WaitConditionNotRunning, container.WaitConditionNextExit, container.WaitConditionRemoved:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid condition: "+string(condition))
+		return
+	}
+
+	e.mu.Lock()
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		e.mu.Unlock()
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+	id, exits := c.ID, c.exits
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	// Keep the last exit status seen so it can be reported after removal
+	var response container.WaitResponse
+	for {
+		e.mu.Lock()
+		c := e.containers[id]
+		if c != nil {
+			response.StatusCode = int64(c.State.ExitCode)
+			response.Error = nil
+			if c.State.Error != "" {
+				response.Error = &container.WaitExitError{Message: c.State.Error}
+			}
+		}
+
+		var done bool
+		switch condition {
+		case container.WaitConditionRemoved:
+			done = c == nil
+		case container.WaitConditionNextExit:
+			done = c == nil || c.exits > exits
+		default:
+			done = c == nil || !c.State.Running
+		}
+		changed := e.stateChanged
+		e.mu.Unlock()
+
+		if done {
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleContainerUpdate serves POST /containers/{id}/update. As in the
+// daemon, zero values in the update leave a setting unchanged.
+func (e *Engine) handleContainerUpdate(w http.ResponseWriter, r *http.Request) {
+	var update container.UpdateConfig
+	if err := decodeBody(r, &update); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	c := e.findContainer(r.PathValue("id"))
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+
+	hc, u := c.HostConfig, update.Resources
+	if hc.AutoRemove && update.RestartPolicy.Name != "" && update.RestartPolicy.Name != container.RestartPolicyDisabled {
+		writeError(w, http.StatusBadRequest, "Restart policy cannot be updated because AutoRemove is enabled for the container")
+		return
+	}
+	if u.Memory != 0 && u.MemorySwap == 0 && hc.MemorySwap > 0 && u.Memory > hc.MemorySwap {
+		writeError(w, http.StatusBadRequest, "Memory limit should be smaller than already set memoryswap limit, update the memoryswap at the same time")
+		return
+	}
+
+	if u.CPUShares != 0 {
+		hc.CPUShares = u.CPUShares
+	}
+	if u.NanoCPUs != 0 {
+		hc.NanoCPUs = u.NanoCPUs
+	}
+	if u.CPUPeriod != 0 {
+		hc.CPUPeriod = u.CPUPeriod
+	}
+	if u.CPUQuota != 0 {
+		hc.CPUQuota = u.CPUQuota
+	}
+	if u.CpusetCpus != "" {
+		hc.CpusetCpus = u.CpusetCpus
+	}
+	if u.CpusetMems != "" {
+		hc.CpusetMems = u.CpusetMems
+	}
+	if u.Memory != 0 {
+		hc.Memory = u.Memory
+	}
+	if u.MemoryReservation != 0 {
+		hc.MemoryReservation = u.MemoryReservation
+	}
+	if u.MemorySwap != 0 {
+		hc.MemorySwap = u.MemorySwap
+	}
+	if u.PidsLimit != nil {
+		hc.PidsLimit = u.PidsLimit
+	}
+	if u.BlkioWeight != 0 {
+		hc.BlkioWeight = u.BlkioWeight
+	}
+	if update.RestartPolicy.Name != "" {
+		hc.RestartPolicy = update.RestartPolicy
+	}
+
+	writeJSON(w, http.StatusOK, container.UpdateResponse{Warnings: []string{}})
+}
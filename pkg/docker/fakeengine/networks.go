@@ -0,0 +1,266 @@
+package fakeengine
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+// predefinedNetworks cannot be removed or pruned
+var predefinedNetworks = map[string]bool{"bridge": true, "host": true, "none": true}
+
+// findNetwork resolves a network by name, ID or unique ID prefix. Callers must hold e.mu.
+func (e *Engine) findNetwork(idOrName string) *network.Inspect {
+	if n, ok := e.networks[idOrName]; ok {
+		return n
+	}
+
+	var match *network.Inspect
+	for _, n := range e.networks {
+		if n.ID == idOrName {
+			return n
+		}
+		if strings.HasPrefix(n.ID, idOrName) {
+			if match != nil {
+				return nil
+			}
+			match = n
+		}
+	}
+	return match
+}
+
+// attach connects a container to a network. Callers must hold e.mu.
+func (e *Engine) attach(n *network.Inspect, c *fakeContainer, settings *network.EndpointSettings) {
+	if settings == nil {
+		settings = &network.EndpointSettings{}
+	}
+	settings.NetworkID = n.ID
+	settings.EndpointID = newID()
+	if settings.IPAddress == "" && n.Driver != "host" && n.Driver != "null" {
+		if settings.IPAMConfig != nil && settings.IPAMConfig.IPv4Address != "" {
+			settings.IPAddress = settings.IPAMConfig.IPv4Address
+		} else {
+			settings.IPAddress = fmt.Sprintf("172.18.0.%d", len(n.Containers)+2)
+		}
+		settings.IPPrefixLen = 16
+	}
+	if settings.IPAMConfig != nil && settings.IPAMConfig.IPv6Address != "" {
+		settings.GlobalIPv6Address = settings.IPAMConfig.IPv6Address
+	}
+
+	c.NetworkSettings.Networks[n.Name] = settings
+
+	resource := network.EndpointResource{
+		Name:       strings.TrimPrefix(c.Name, "/"),
+		EndpointID: settings.EndpointID,
+	}
+	if settings.IPAddress != "" {
+		resource.IPv4Address = fmt.Sprintf("%s/%d", settings.IPAddress, settings.IPPrefixLen)
+	}
+	n.Containers[c.ID] = resource
+}
+
+// handleNetworkList serves GET /networks
+func (e *Engine) handleNetworkList(w http.ResponseWriter, r *http.Request) {
+	filterArgs, err := queryFilters(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := []network.Summary{}
+	for _, n := range e.networks {
+		if filterArgs.Contains("name") && !filterArgs.Match("name", n.Name) {
+			continue
+		}
+		if filterArgs.Contains("driver") && !filterArgs.ExactMatch("driver", n.Driver) {
+			continue
+		}
+		if !filterArgs.MatchKVList("label", n.Labels) {
+			continue
+		}
+		summary := *n
+		summary.Containers = nil
+		result = append(result, summary)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleNetworkCreate serves POST /networks/create
+func (e *Engine) handleNetworkCreate(w http.ResponseWriter, r *http.Request) {
+	var req network.CreateRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "network name is required")
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.networks[req.Name]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("network with name %s already exists", req.Name))
+		return
+	}
+
+	n := &network.Inspect{
+		Name:       req.Name,
+		ID:         newID(),
+		Created:    time.Now(),
+		Scope:      "local",
+		Driver:     req.Driver,
+		EnableIPv4: true,
+		Internal:   req.Internal,
+		Attachable: req.Attachable,
+		Options:    req.Options,
+		Labels:     req.Labels,
+		Containers: map[string]network.EndpointResource{},
+	}
+	if n.Driver == "" {
+		n.Driver = "bridge"
+	}
+	if req.EnableIPv6 != nil {
+		n.EnableIPv6 = *req.EnableIPv6
+	}
+	if req.IPAM != nil {
+		n.IPAM = *req.IPAM
+	}
+	if n.IPAM.Driver == "" {
+		n.IPAM.Driver = "default"
+	}
+
+	e.networks[n.Name] = n
+	writeJSON(w, http.StatusCreated, network.CreateResponse{ID: n.ID})
+}
+
+// handleNetworkInspect serves GET /networks/{id}
+func (e *Engine) handleNetworkInspect(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	n := e.findNetwork(r.PathValue("id"))
+	if n == nil {
+		writeError(w, http.StatusNotFound, "network "+r.PathValue("id")+" not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, n)
+}
+
+// handleNetworkRemove serves DELETE /networks/{id}
+func (e *Engine) handleNetworkRemove(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	n := e.findNetwork(r.PathValue("id"))
+	if n == nil {
+		writeError(w, http.StatusNotFound, "network "+r.PathValue("id")+" not found")
+		return
+	}
+	if predefinedNetworks[n.Name] {
+		writeError(w, http.StatusForbidden, n.Name+" is a pre-defined network and cannot be removed")
+		return
+	}
+	if len(n.Containers) > 0 {
+		writeError(w, http.StatusForbidden, "error while removing network: network "+n.Name+" has active endpoints")
+		return
+	}
+
+	delete(e.networks, n.Name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleNetworkPrune serves POST /networks/prune
+func (e *Engine) handleNetworkPrune(w http.ResponseWriter, r *http.Request) {
+	filterArgs, err := queryFilters(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	report := network.PruneReport{NetworksDeleted: []string{}}
+	for name, n := range e.networks {
+		if predefinedNetworks[name] || len(n.Containers) > 0 {
+			continue
+		}
+		if !filterArgs.MatchKVList("label", n.Labels) {
+			continue
+		}
+		delete(e.networks, name)
+		report.NetworksDeleted = append(report.NetworksDeleted, name)
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleNetworkConnect serves POST /networks/{id}/connect
+func (e *Engine) handleNetworkConnect(w http.ResponseWriter, r *http.Request) {
+	var opts network.ConnectOptions
+	if err := decodeBody(r, &opts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	n := e.findNetwork(r.PathValue("id"))
+	if n == nil {
+		writeError(w, http.StatusNotFound, "network "+r.PathValue("id")+" not found")
+		return
+	}
+	c := e.findContainer(opts.Container)
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+opts.Container)
+		return
+	}
+	if _, ok := n.Containers[c.ID]; ok {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("endpoint with name %s already exists in network %s", strings.TrimPrefix(c.Name, "/"), n.Name))
+		return
+	}
+
+	e.attach(n, c, opts.EndpointConfig)
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleNetworkDisconnect serves POST /networks/{id}/disconnect
+func (e *Engine) handleNetworkDisconnect(w http.ResponseWriter, r *http.Request) {
+	var opts network.DisconnectOptions
+	if err := decodeBody(r, &opts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	n := e.findNetwork(r.PathValue("id"))
+	if n == nil {
+		writeError(w, http.StatusNotFound, "network "+r.PathValue("id")+" not found")
+		return
+	}
+	c := e.findContainer(opts.Container)
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+opts.Container)
+		return
+	}
+	if _, ok := n.Containers[c.ID]; !ok {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("container %s is not connected to network %s", c.ID, n.Name))
+		return
+	}
+
+	delete(n.Containers, c.ID)
+	delete(c.NetworkSettings.Networks, n.Name)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,81 @@
+package fakeengine
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Default resource usage reported for running containers
+const (
+	defaultOnlineCPUs  = 2
+	defaultMemoryUsage = 64 << 20
+	defaultMemoryCache = 16 << 20
+	defaultMemoryLimit = 1 << 30
+	defaultNetworkRate = 1000 // Bytes per second received, and half that sent
+	defaultBlockIORate = 4096 // Bytes per second read, and half that written
+)
+
+// handleContainerStats serves GET /containers/{id}/stats as a single sample.
+// Stopped containers report empty stats, as the daemon does.
+func (e *Engine) handleContainerStats(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	c := e.findContainer(r.PathValue("id"))
+	var (
+		id        string
+		running   bool
+		startedAt time.Time
+	)
+	if c != nil {
+		id = c.ID
+		running = c.State.Running
+		startedAt, _ = time.Parse(time.RFC3339Nano, c.State.StartedAt)
+	}
+	handler := e.StatsHandler
+	e.mu.Unlock()
+
+	if c == nil {
+		writeError(w, http.StatusNotFound, "No such container: "+r.PathValue("id"))
+		return
+	}
+
+	stats := container.StatsResponse{ID: id}
+	if running {
+		if handler == nil {
+			stats = defaultStats(id, startedAt)
+		} else {
+			stats = handler(id)
+		}
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// defaultStats simulates a container using a quarter of one CPU and steady
+// network and disk traffic since it started
+func defaultStats(id string, startedAt time.Time) container.StatsResponse {
+	now := time.Now()
+	elapsed := now.Sub(startedAt)
+	seconds := elapsed.Seconds()
+
+	stats := container.StatsResponse{ID: id, Read: now}
+	stats.CPUStats = container.CPUStats{
+		CPUUsage:    container.CPUUsage{TotalUsage: uint64(elapsed / 4)},
+		SystemUsage: uint64(elapsed * defaultOnlineCPUs),
+		OnlineCPUs:  defaultOnlineCPUs,
+	}
+	stats.MemoryStats = container.MemoryStats{
+		Usage: defaultMemoryUsage,
+		Limit: defaultMemoryLimit,
+		Stats: map[string]uint64{"inactive_file": defaultMemoryCache},
+	}
+	stats.Networks = map[string]container.NetworkStats{
+		"eth0": {RxBytes: uint64(seconds * defaultNetworkRate), TxBytes: uint64(seconds * defaultNetworkRate / 2)},
+	}
+	stats.BlkioStats.IoServiceBytesRecursive = []container.BlkioStatEntry{
+		{Op: "read", Value: uint64(seconds * defaultBlockIORate)},
+		{Op: "write", Value: uint64(seconds * defaultBlockIORate / 2)},
+	}
+	stats.PidsStats = container.PidsStats{Current: 1}
+	return stats
+}
@@ -0,0 +1,183 @@
+package fakeengine
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// createVolume stores a new volume; an empty name creates an anonymous volume. Callers must hold e.mu.
+func (e *Engine) createVolume(opts volume.CreateOptions) *fakeVolume {
+	anonymous := opts.Name == ""
+	if anonymous {
+		opts.Name = newID()
+	}
+	if opts.Driver == "" {
+		opts.Driver = "local"
+	}
+
+	v := &fakeVolume{
+		Volume: volume.Volume{
+			Name:       opts.Name,
+			Driver:     opts.Driver,
+			Mountpoint: "/var/lib/docker/volumes/" + opts.Name + "/_data",
+			Scope:      "local",
+			Labels:     opts.Labels,
+			Options:    opts.DriverOpts,
+			CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		},
+		anonymous: anonymous,
+	}
+	if v.Labels == nil {
+		v.Labels = map[string]string{}
+	}
+	if v.Options == nil {
+		v.Options = map[string]string{}
+	}
+	e.volumes[v.Name] = v
+	return v
+}
+
+// volumeInUse reports whether any container mounts the volume. Callers must hold e.mu.
+func (e *Engine) volumeInUse(name string) bool {
+	for _, c := range e.containers {
+		for _, m := range c.Mounts {
+			if m.Type == mount.TypeVolume && m.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleVolumeList serves GET /volumes
+func (e *Engine) handleVolumeList(w http.ResponseWriter, r *http.Request) {
+	filterArgs, err := queryFilters(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := volume.ListResponse{Volumes: []*volume.Volume{}, Warnings: []string{}}
+	for _, v := range e.volumes {
+		if filterArgs.Contains("name") && !filterArgs.Match("name", v.Name) {
+			continue
+		}
+		if filterArgs.Contains("driver") && !filterArgs.ExactMatch("driver", v.Driver) {
+			continue
+		}
+		if filterArgs.Contains("dangling") {
+			dangling := !e.volumeInUse(v.Name)
+			if !filterArgs.ExactMatch("dangling", strconv.FormatBool(dangling)) && !filterArgs.ExactMatch("dangling", boolDigit(dangling)) {
+				continue
+			}
+		}
+		if !filterArgs.MatchKVList("label", v.Labels) {
+			continue
+		}
+		vol := v.Volume
+		result.Volumes = append(result.Volumes, &vol)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleVolumeCreate serves POST /volumes/create
+func (e *Engine) handleVolumeCreate(w http.ResponseWriter, r *http.Request) {
+	var opts volume.CreateOptions
+	if err := decodeBody(r, &opts); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Creating an existing volume returns it unchanged, as the engine does
+	if v, ok := e.volumes[opts.Name]; ok && opts.Name != "" {
+		writeJSON(w, http.StatusCreated, v.Volume)
+		return
+	}
+
+	v := e.createVolume(opts)
+	writeJSON(w, http.StatusCreated, v.Volume)
+}
+
+// handleVolumeInspect serves GET /volumes/{name}
+func (e *Engine) handleVolumeInspect(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	v, ok := e.volumes[r.PathValue("name")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "get "+r.PathValue("name")+": no such volume")
+		return
+	}
+	writeJSON(w, http.StatusOK, v.Volume)
+}
+
+// handleVolumeRemove serves DELETE /volumes/{name}
+func (e *Engine) handleVolumeRemove(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.volumes[name]; !ok {
+		if queryBool(r, "force") {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeError(w, http.StatusNotFound, "get "+name+": no such volume")
+		return
+	}
+	if e.volumeInUse(name) {
+		writeError(w, http.StatusConflict, fmt.Sprintf("remove %s: volume is in use", name))
+		return
+	}
+
+	delete(e.volumes, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleVolumePrune serves POST /volumes/prune. Only anonymous volumes are
+// removed unless the "all" filter is set.
+func (e *Engine) handleVolumePrune(w http.ResponseWriter, r *http.Request) {
+	filterArgs, err := queryFilters(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	all := filterArgs.Contains("all") && (filterArgs.ExactMatch("all", "true") || filterArgs.ExactMatch("all", "1"))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	report := volume.PruneReport{VolumesDeleted: []string{}}
+	for name, v := range e.volumes {
+		if (!all && !v.anonymous) || e.volumeInUse(name) {
+			continue
+		}
+		if !filterArgs.MatchKVList("label", v.Labels) {
+			continue
+		}
+		delete(e.volumes, name)
+		report.VolumesDeleted = append(report.VolumesDeleted, name)
+		report.SpaceReclaimed += 4096
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// boolDigit formats b as "1" or "0"
+func boolDigit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
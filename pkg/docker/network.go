@@ -0,0 +1,45 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+)
+
+// ListNetworks lists networks matching the given filters
+func (c *Client) ListNetworks(ctx context.Context, filterArgs filters.Args) ([]network.Summary, error) {
+	return c.dockerClient.NetworkList(ctx, network.ListOptions{
+		Filters: filterArgs,
+	})
+}
+
+// InspectNetwork retrieves detailed information about a network
+func (c *Client) InspectNetwork(ctx context.Context, networkID string) (network.Inspect, error) {
+	return c.dockerClient.NetworkInspect(ctx, networkID, network.InspectOptions{})
+}
+
+// CreateNetwork creates a new network
+func (c *Client) CreateNetwork(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+	return c.dockerClient.NetworkCreate(ctx, name, options)
+}
+
+// RemoveNetwork removes a network
+func (c *Client) RemoveNetwork(ctx context.Context, networkID string) error {
+	return c.dockerClient.NetworkRemove(ctx, networkID)
+}
+
+// PruneNetworks removes all unused networks matching the given filters
+func (c *Client) PruneNetworks(ctx context.Context, filterArgs filters.Args) (network.PruneReport, error) {
+	return c.dockerClient.NetworksPrune(ctx, filterArgs)
+}
+
+// ConnectNetwork connects a container to a network
+func (c *Client) ConnectNetwork(ctx context.Context, networkID, containerID string, settings *network.EndpointSettings) error {
+	return c.dockerClient.NetworkConnect(ctx, networkID, containerID, settings)
+}
+
+// DisconnectNetwork disconnects a container from a network
+func (c *Client) DisconnectNetwork(ctx context.Context, networkID, containerID string, force bool) error {
+	return c.dockerClient.NetworkDisconnect(ctx, networkID, containerID, force)
+}
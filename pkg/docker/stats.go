@@ -0,0 +1,26 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerStats returns a single sample of a container's resource usage.
+// The sample's counters are cumulative; rates need two samples.
+func (c *Client) ContainerStats(ctx context.Context, containerID string) (container.StatsResponse, error) {
+	var stats container.StatsResponse
+
+	resp, err := c.dockerClient.ContainerStatsOneShot(ctx, containerID)
+	if err != nil {
+		return stats, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return stats, fmt.Errorf("failed to decode stats: %w", err)
+	}
+	return stats, nil
+}
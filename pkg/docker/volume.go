@@ -0,0 +1,35 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// ListVolumes lists volumes matching the given filters
+func (c *Client) ListVolumes(ctx context.Context, filterArgs filters.Args) (volume.ListResponse, error) {
+	return c.dockerClient.VolumeList(ctx, volume.ListOptions{
+		Filters: filterArgs,
+	})
+}
+
+// InspectVolume retrieves detailed information about a volume
+func (c *Client) InspectVolume(ctx context.Context, volumeName string) (volume.Volume, error) {
+	return c.dockerClient.VolumeInspect(ctx, volumeName)
+}
+
+// CreateVolume creates a new named volume
+func (c *Client) CreateVolume(ctx context.Context, options volume.CreateOptions) (volume.Volume, error) {
+	return c.dockerClient.VolumeCreate(ctx, options)
+}
+
+// RemoveVolume removes a volume
+func (c *Client) RemoveVolume(ctx context.Context, volumeName string, force bool) error {
+	return c.dockerClient.VolumeRemove(ctx, volumeName, force)
+}
+
+// PruneVolumes removes unused volumes matching the given filters
+func (c *Client) PruneVolumes(ctx context.Context, filterArgs filters.Args) (volume.PruneReport, error) {
+	return c.dockerClient.VolumesPrune(ctx, filterArgs)
+}
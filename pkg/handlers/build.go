@@ -0,0 +1,434 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/coolbit-in/docker-mcp/pkg/docker"
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// buildKitTraceID marks aux messages carrying encoded BuildKit progress rather than the image ID
+const buildKitTraceID = "moby.buildkit.trace"
+
+// buildFailureLines is how much of the failing step's output a build error includes
+const buildFailureLines = 20
+
+// buildStepPattern matches the step header printed by the legacy builder, e.g. "Step 2/5 : RUN make"
+var buildStepPattern = regexp.MustCompile(`^Step (\d+/\d+) : (.*)$`)
+
+// legacyBuiltPattern matches the final line of a legacy build when no aux ID was sent
+var legacyBuiltPattern = regexp.MustCompile(`^Successfully built ([0-9a-f]+)$`)
+
+// HandleBuildImage handles image build requests
+func (h *Handler) HandleBuildImage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	options, err := buildOptions(params)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	buildContext, err := buildContextFromParams(params, options.Dockerfile)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	dockerfile, err := buildContext.Dockerfile(options.Dockerfile)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+	if err := h.options.Policy.CheckBuild(options, dockerfile); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	archive, err := buildContext.Archive(options.Dockerfile, h.options.MaxBuildContextSize)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to prepare build context: %w", err))
+	}
+
+	if h.dryRun(params) {
+		return h.formatResponse(h.planBuildImage(ctx, archive, options))
+	}
+
+	resp, err := h.dockerClient.BuildImage(ctx, archive, options)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to build image: %w", err))
+	}
+	defer resp.Body.Close()
+
+	var log buildLog
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return h.formatErrorResponse(fmt.Errorf("failed to read build output: %w", err))
+		}
+		log.apply(msg)
+		if log.err != "" {
+			break
+		}
+	}
+	log.flush()
+	if log.err != "" {
+		return h.formatErrorResponse(log.failure())
+	}
+
+	// Fall back to the tag when the daemon did not report the ID in the stream
+	imageID := log.imageID
+	if imageID == "" {
+		inspect, err := h.dockerClient.InspectImage(ctx, options.Tags[0])
+		if err != nil {
+			return h.formatErrorResponse(fmt.Errorf("build finished without reporting an image ID: %w", err))
+		}
+		imageID = inspect.ID
+	}
+
+	return h.formatResponse(models.BuildImageResponse{
+		Success:      true,
+		ImageID:      imageID,
+		Tags:         options.Tags,
+		Steps:        log.steps,
+		ContextSize:  archive.Size,
+		ContextFiles: archive.Files,
+		IgnoredFiles: archive.Ignored,
+	})
+}
+
+// buildOptions converts the build_image parameters into Docker build options
+func buildOptions(params map[string]interface{}) (types.ImageBuildOptions, error) {
+	options := types.ImageBuildOptions{
+		Dockerfile: "Dockerfile",
+		Remove:     true,
+		Labels:     stringMap(params, "labels"),
+		CacheFrom:  stringSlice(params, "cache_from"),
+		ExtraHosts: stringSlice(params, "extra_hosts"),
+	}
+
+	if df, ok := params["dockerfile"].(string); ok && df != "" {
+		options.Dockerfile = df
+	}
+
+	// Both the single tag and the tags array are accepted; duplicates are dropped
+	seen := make(map[string]bool)
+	candidates := stringSlice(params, "tags")
+	if tag, ok := params["tag"].(string); ok && tag != "" {
+		candidates = append([]string{tag}, candidates...)
+	}
+	for _, tag := range candidates {
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			options.Tags = append(options.Tags, tag)
+		}
+	}
+	if len(options.Tags) == 0 {
+		return options, fmt.Errorf("tag or tags is required")
+	}
+
+	if noCacheVal, ok := params["no_cache"].(bool); ok {
+		options.NoCache = noCacheVal
+	}
+	if pullVal, ok := params["pull"].(bool); ok {
+		options.PullParent = pullVal
+	}
+
+	if args := stringMap(params, "build_args"); len(args) > 0 {
+		options.BuildArgs = make(map[string]*string, len(args))
+		for k, v := range args {
+			options.BuildArgs[k] = &v
+		}
+	}
+
+	if target, ok := params["target"].(string); ok {
+		options.Target = target
+	}
+	if platform, ok := params["platform"].(string); ok {
+		options.Platform = platform
+	}
+	if networkMode, ok := params["network"].(string); ok {
+		options.NetworkMode = networkMode
+	}
+
+	for _, host := range options.ExtraHosts {
+		name, ip, ok := strings.Cut(host, ":")
+		if !ok || name == "" || ip == "" {
+			return options, fmt.Errorf("invalid extra host %q: expected host:ip", host)
+		}
+	}
+
+	if shmVal, ok := params["shm_size"].(float64); ok {
+		if shmVal < 0 {
+			return options, fmt.Errorf("shm_size must not be negative")
+		}
+		options.ShmSize = int64(shmVal)
+	}
+
+	return options, nil
+}
+
+// buildContextFromParams selects the build context: a server directory
+// (context_path), a remote git or tarball URL (context_url), or files sent
+// with the request (dockerfile_content and files) packed in memory.
+func buildContextFromParams(params map[string]interface{}, dockerfile string) (docker.BuildContext, error) {
+	var buildContext docker.BuildContext
+	var sources []string
+
+	if contextPath, ok := params["context_path"].(string); ok && contextPath != "" {
+		buildContext.Dir = contextPath
+		sources = append(sources, "context_path")
+	}
+
+	if contextURL, ok := params["context_url"].(string); ok && contextURL != "" {
+		if !isRemoteContext(contextURL) {
+			return buildContext, fmt.Errorf("invalid context_url %q: expected an http(s) tarball URL or a git repository URL", contextURL)
+		}
+		buildContext.RemoteURL = contextURL
+		sources = append(sources, "context_url")
+	}
+
+	files, err := contextFiles(params)
+	if err != nil {
+		return buildContext, err
+	}
+	if content, ok := params["dockerfile_content"].(string); ok && content != "" {
+		if files == nil {
+			files = make(map[string]docker.BuildFile)
+		}
+		if _, exists := files[dockerfile]; exists {
+			return buildContext, fmt.Errorf("files must not contain %s when dockerfile_content is given", dockerfile)
+		}
+		files[dockerfile] = docker.BuildFile{Content: []byte(content)}
+	}
+	if len(files) > 0 {
+		buildContext.Files = files
+		sources = append(sources, "dockerfile_content/files")
+	}
+
+	switch len(sources) {
+	case 0:
+		return buildContext, fmt.Errorf("one of context_path, context_url or dockerfile_content is required")
+	case 1:
+		return buildContext, nil
+	default:
+		return buildContext, fmt.Errorf("only one build context may be given, got %s", strings.Join(sources, " and "))
+	}
+}
+
+// contextFiles decodes the files parameter. Each value is either the file's
+// text or an object with content, encoding ("utf-8" or "base64") and mode
+// (octal permission string).
+func contextFiles(params map[string]interface{}) (map[string]docker.BuildFile, error) {
+	obj, ok := params["files"].(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		return nil, nil
+	}
+
+	files := make(map[string]docker.BuildFile, len(obj))
+	for name, value := range obj {
+		var file docker.BuildFile
+		switch v := value.(type) {
+		case string:
+			file.Content = []byte(v)
+		case map[string]interface{}:
+			content, _ := v["content"].(string)
+			encoding, _ := v["encoding"].(string)
+			switch encoding {
+			case "", "utf-8":
+				file.Content = []byte(content)
+			case "base64":
+				data, err := base64.StdEncoding.DecodeString(content)
+				if err != nil {
+					return nil, fmt.Errorf("invalid base64 content for file %s: %w", name, err)
+				}
+				file.Content = data
+			default:
+				return nil, fmt.Errorf("invalid encoding %q for file %s: expected utf-8 or base64", encoding, name)
+			}
+			if modeVal, ok := v["mode"].(string); ok && modeVal != "" {
+				mode, err := strconv.ParseInt(modeVal, 8, 32)
+				if err != nil || mode < 0 || mode > 0o7777 {
+					return nil, fmt.Errorf("invalid mode %q for file %s: expected an octal permission string", modeVal, name)
+				}
+				file.Mode = mode
+			}
+		default:
+			return nil, fmt.Errorf("invalid file %s: expected a string or an object with content", name)
+		}
+		files[name] = file
+	}
+	return files, nil
+}
+
+// isRemoteContext reports whether url is a build context the daemon can fetch itself
+func isRemoteContext(url string) bool {
+	for _, prefix := range []string{"http://", "https://", "git://", "git@"} {
+		if strings.HasPrefix(url, prefix) && len(url) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildLog assembles the JSON message stream of a build into steps, from
+// the legacy builder's output or from BuildKit traces
+type buildLog struct {
+	steps        []models.BuildStep
+	output       strings.Builder // Output of the current legacy step
+	partial      string          // Unterminated text from the previous stream message
+	vertexes     map[string]int  // BuildKit vertex digest -> index in steps
+	failedVertex string          // Digest of the BuildKit vertex that reported an error
+	imageID      string
+	err          string
+}
+
+// apply consumes one message from the build stream
+func (l *buildLog) apply(msg jsonmessage.JSONMessage) {
+	switch {
+	case msg.Error != nil && msg.Error.Message != "":
+		l.err = msg.Error.Message
+	case msg.ErrorMessage != "":
+		l.err = msg.ErrorMessage
+	case msg.Aux != nil && msg.ID == buildKitTraceID:
+		l.trace(*msg.Aux)
+	case msg.Aux != nil:
+		var aux struct {
+			ID string
+		}
+		if err := json.Unmarshal(*msg.Aux, &aux); err == nil && aux.ID != "" {
+			l.imageID = aux.ID
+		}
+	case msg.Stream != "":
+		text := l.partial + msg.Stream
+		lines := strings.Split(text, "\n")
+		l.partial = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			l.line(line)
+		}
+	}
+}
+
+// line records one line of builder output
+func (l *buildLog) line(line string) {
+	line = strings.TrimRight(line, "\r")
+
+	if m := buildStepPattern.FindStringSubmatch(line); m != nil {
+		l.endStep()
+		l.steps = append(l.steps, models.BuildStep{Step: m[1], Instruction: m[2]})
+		return
+	}
+	if m := legacyBuiltPattern.FindStringSubmatch(line); m != nil {
+		if l.imageID == "" {
+			l.imageID = m[1]
+		}
+		return
+	}
+
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "---> Using cache":
+		if n := len(l.steps); n > 0 {
+			l.steps[n-1].Cached = true
+		}
+		return
+	case strings.HasPrefix(trimmed, "---> "),
+		strings.HasPrefix(trimmed, "Removing intermediate container "),
+		strings.HasPrefix(trimmed, "Successfully tagged "):
+		// Builder bookkeeping, not step output
+		return
+	}
+	if len(l.steps) > 0 {
+		l.output.WriteString(line)
+		l.output.WriteString("\n")
+	}
+}
+
+// trace records the steps and output reported by a BuildKit trace message.
+// Vertices that do not run a Dockerfile instruction, such as loading the
+// context, are left out.
+func (l *buildLog) trace(aux json.RawMessage) {
+	vertexes, logs, err := decodeBuildKitTrace(aux)
+	if err != nil {
+		// Progress is informational; the build result does not depend on it
+		return
+	}
+
+	for _, v := range vertexes {
+		i, ok := l.vertexes[v.digest]
+		if !ok {
+			m := buildKitStepPattern.FindStringSubmatch(v.name)
+			if m == nil {
+				continue
+			}
+			if l.vertexes == nil {
+				l.vertexes = make(map[string]int)
+			}
+			i = len(l.steps)
+			l.vertexes[v.digest] = i
+			l.steps = append(l.steps, models.BuildStep{Step: m[1], Instruction: m[2]})
+		}
+		if v.cached {
+			l.steps[i].Cached = true
+		}
+		if v.err != "" {
+			l.failedVertex = v.digest
+		}
+	}
+	for _, entry := range logs {
+		if i, ok := l.vertexes[entry.vertex]; ok {
+			l.steps[i].Output += string(entry.msg)
+		}
+	}
+}
+
+// endStep stores the collected output on the current legacy step
+func (l *buildLog) endStep() {
+	if n := len(l.steps); n > 0 && l.output.Len() > 0 {
+		l.steps[n-1].Output = l.output.String()
+	}
+	l.output.Reset()
+}
+
+// failure describes a failed build with the step that failed and the end of its output
+func (l *buildLog) failure() error {
+	var step *models.BuildStep
+	if i, ok := l.vertexes[l.failedVertex]; ok && l.failedVertex != "" {
+		step = &l.steps[i]
+	} else if n := len(l.steps); n > 0 {
+		step = &l.steps[n-1]
+	}
+	if step == nil {
+		return fmt.Errorf("build failed: %s", l.err)
+	}
+
+	message := fmt.Sprintf("build failed at step %s (%s): %s", step.Step, step.Instruction, l.err)
+	lines := strings.Split(strings.TrimRight(step.Output, "\n"), "\n")
+	if len(lines) > buildFailureLines {
+		lines = lines[len(lines)-buildFailureLines:]
+	}
+	if tail := strings.Join(lines, "\n"); tail != "" {
+		message += "\n" + tail
+	}
+	return errors.New(message)
+}
+
+// flush processes any unterminated output and closes the last step
+func (l *buildLog) flush() {
+	if l.partial != "" {
+		l.line(l.partial)
+		l.partial = ""
+	}
+	l.endStep()
+}
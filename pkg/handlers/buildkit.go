@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// buildKitStepPattern matches the name of a BuildKit vertex running a
+// Dockerfile instruction, e.g. "[2/5] RUN make" or "[builder 2/5] RUN make"
+var buildKitStepPattern = regexp.MustCompile(`^\[((?:\S+ )?\d+/\d+)\] (.*)$`)
+
+// Field numbers of the moby.buildkit.v1.StatusResponse messages sent in build traces
+const (
+	statusVertexes  protowire.Number = 1 // StatusResponse.vertexes
+	statusLogs      protowire.Number = 3 // StatusResponse.logs
+	vertexDigest    protowire.Number = 1 // Vertex.digest
+	vertexName      protowire.Number = 3 // Vertex.name
+	vertexCached    protowire.Number = 4 // Vertex.cached
+	vertexError     protowire.Number = 7 // Vertex.error
+	vertexLogVertex protowire.Number = 1 // VertexLog.vertex
+	vertexLogMsg    protowire.Number = 4 // VertexLog.msg
+)
+
+// buildKitVertex is one build operation reported in a BuildKit trace
+type buildKitVertex struct {
+	digest string
+	name   string
+	cached bool
+	err    string
+}
+
+// buildKitLog is output of a build operation reported in a BuildKit trace
+type buildKitLog struct {
+	vertex string
+	msg    []byte
+}
+
+// decodeBuildKitTrace decodes the aux payload of a moby.buildkit.trace
+// message: a base64 JSON string holding a protobuf StatusResponse
+func decodeBuildKitTrace(aux json.RawMessage) ([]buildKitVertex, []buildKitLog, error) {
+	var data []byte
+	if err := json.Unmarshal(aux, &data); err != nil {
+		return nil, nil, fmt.Errorf("invalid BuildKit trace: %w", err)
+	}
+
+	var vertexes []buildKitVertex
+	var logs []buildKitLog
+	err := protoFields(data, func(num protowire.Number, value []byte, _ uint64) error {
+		switch num {
+		case statusVertexes:
+			var v buildKitVertex
+			err := protoFields(value, func(num protowire.Number, value []byte, varint uint64) error {
+				switch num {
+				case vertexDigest:
+					v.digest = string(value)
+				case vertexName:
+					v.name = string(value)
+				case vertexCached:
+					v.cached = varint != 0
+				case vertexError:
+					v.err = string(value)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			vertexes = append(vertexes, v)
+		case statusLogs:
+			var l buildKitLog
+			err := protoFields(value, func(num protowire.Number, value []byte, _ uint64) error {
+				switch num {
+				case vertexLogVertex:
+					l.vertex = string(value)
+				case vertexLogMsg:
+					l.msg = value
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			logs = append(logs, l)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid BuildKit trace: %w", err)
+	}
+	return vertexes, logs, nil
+}
+
+// protoFields calls fn for each field of a protobuf message with its
+// length-delimited value or its varint; other wire types are skipped
+func protoFields(data []byte, fn func(num protowire.Number, value []byte, varint uint64) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var value []byte
+		var varint uint64
+		switch typ {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if typ == protowire.BytesType || typ == protowire.VarintType {
+			if err := fn(num, value, varint); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,417 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+)
+
+// hostnamePattern matches RFC 1123 host names
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// capabilityPattern matches capability names, with or without the CAP_ prefix
+var capabilityPattern = regexp.MustCompile(`^(CAP_)?[A-Z][A-Z0-9_]*$`)
+
+// securityOptKeys are the key=value security options a container may set
+var securityOptKeys = map[string]bool{"label": true, "apparmor": true, "seccomp": true, "systempaths": true}
+
+// portBindings parses the ports parameter of create_container. Keys are
+// [host_ip:]host_port:container_port[/protocol], or a container port whose
+// value is the host port. The protocol defaults to tcp and an empty host
+// port lets the daemon pick one.
+func portBindings(params map[string]interface{}) (nat.PortSet, nat.PortMap, error) {
+	portMap, ok := params["ports"].(map[string]interface{})
+	if !ok || len(portMap) == 0 {
+		return nil, nil, nil
+	}
+
+	// Sort the keys so bindings of the same port keep a stable order
+	keys := make([]string, 0, len(portMap))
+	for key := range portMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	exposed := nat.PortSet{}
+	bindings := nat.PortMap{}
+	for _, key := range keys {
+		spec := key
+		if !strings.Contains(key, ":") {
+			switch hostPort := portMap[key].(type) {
+			case string:
+				spec = hostPort + ":" + key
+			case float64:
+				spec = strconv.FormatFloat(hostPort, 'f', -1, 64) + ":" + key
+			}
+		}
+
+		mappings, err := nat.ParsePortSpec(spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid port mapping %q: %w", key, err)
+		}
+		for _, m := range mappings {
+			if m.Binding.HostIP == "" {
+				m.Binding.HostIP = "0.0.0.0"
+			}
+			exposed[m.Port] = struct{}{}
+			bindings[m.Port] = append(bindings[m.Port], m.Binding)
+		}
+	}
+	return exposed, bindings, nil
+}
+
+// applyContainerOptions applies the optional process, identity and health
+// settings of create_container to the container configuration
+func applyContainerOptions(params map[string]interface{}, config *container.Config) error {
+	if _, ok := params["entrypoint"]; ok {
+		// An empty entrypoint clears the one the image sets
+		config.Entrypoint = stringSlice(params, "entrypoint")
+		if len(config.Entrypoint) == 0 {
+			config.Entrypoint = []string{""}
+		}
+	}
+
+	if user, ok := params["user"].(string); ok && user != "" {
+		config.User = user
+	}
+
+	if hostname, ok := params["hostname"].(string); ok && hostname != "" {
+		if len(hostname) > 253 || !hostnamePattern.MatchString(hostname) {
+			return fmt.Errorf("invalid hostname %q: expected an RFC 1123 host name", hostname)
+		}
+		config.Hostname = hostname
+	}
+
+	if labels := stringMap(params, "labels"); labels != nil {
+		for key := range labels {
+			if strings.TrimSpace(key) == "" {
+				return fmt.Errorf("label keys must not be empty")
+			}
+		}
+		config.Labels = labels
+	}
+
+	if signal, ok := params["stop_signal"].(string); ok && signal != "" {
+		normalized, err := normalizeSignal(signal)
+		if err != nil {
+			return fmt.Errorf("invalid stop_signal: %w", err)
+		}
+		config.StopSignal = normalized
+	}
+
+	if timeoutVal, ok := params["stop_timeout"]; ok {
+		timeout, ok := timeoutVal.(float64)
+		if !ok || timeout < 0 || timeout != float64(int(timeout)) {
+			return fmt.Errorf("stop_timeout must be a whole number of seconds, 0 or more")
+		}
+		seconds := int(timeout)
+		config.StopTimeout = &seconds
+	}
+
+	health, err := healthcheck(params)
+	if err != nil {
+		return err
+	}
+	config.Healthcheck = health
+
+	return nil
+}
+
+// healthcheck parses the healthcheck parameter of create_container. A string
+// test runs with the container's shell; an array runs directly unless it
+// starts with CMD or CMD-SHELL.
+func healthcheck(params map[string]interface{}) (*container.HealthConfig, error) {
+	spec, ok := params["healthcheck"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	if disable, _ := spec["disable"].(bool); disable {
+		if len(spec) > 1 {
+			return nil, fmt.Errorf("healthcheck disable cannot be combined with other healthcheck settings")
+		}
+		return &container.HealthConfig{Test: []string{"NONE"}}, nil
+	}
+
+	health := &container.HealthConfig{}
+	switch test := spec["test"].(type) {
+	case string:
+		if test == "" {
+			return nil, fmt.Errorf("healthcheck test must not be empty")
+		}
+		health.Test = []string{"CMD-SHELL", test}
+	case []interface{}:
+		args := stringSlice(spec, "test")
+		switch {
+		case len(args) == 0:
+			return nil, fmt.Errorf("healthcheck test must not be empty")
+		case args[0] == "NONE":
+			return nil, fmt.Errorf("use healthcheck disable instead of a NONE test")
+		case args[0] == "CMD" || args[0] == "CMD-SHELL":
+			if len(args) < 2 {
+				return nil, fmt.Errorf("healthcheck test %s needs a command", args[0])
+			}
+			health.Test = args
+		default:
+			health.Test = append([]string{"CMD"}, args...)
+		}
+	default:
+		return nil, fmt.Errorf("healthcheck test is required: a shell command string or an array")
+	}
+
+	for _, field := range []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"interval", &health.Interval},
+		{"timeout", &health.Timeout},
+		{"start_period", &health.StartPeriod},
+		{"start_interval", &health.StartInterval},
+	} {
+		value, ok := spec[field.name]
+		if !ok {
+			continue
+		}
+		s, _ := value.(string)
+		d, err := time.ParseDuration(s)
+		// As in the daemon, zero means the default and anything else must be at least 1ms
+		if err != nil || d < 0 || (d > 0 && d < time.Millisecond) {
+			return nil, fmt.Errorf("invalid healthcheck %s %v: expected a duration of at least 1ms such as 30s", field.name, value)
+		}
+		*field.dst = d
+	}
+
+	if retriesVal, ok := spec["retries"]; ok {
+		retries, ok := retriesVal.(float64)
+		if !ok || retries < 0 || retries != float64(int(retries)) {
+			return nil, fmt.Errorf("healthcheck retries must be a whole number, 0 or more")
+		}
+		health.Retries = int(retries)
+	}
+
+	return health, nil
+}
+
+// applyHostOptions applies the optional resource, security, DNS and logging
+// settings of create_container to the host configuration
+func applyHostOptions(params map[string]interface{}, hostConfig *container.HostConfig) error {
+	// Resource limits take the same values as update_container
+	var update container.UpdateConfig
+	for _, setting := range updateSettings {
+		value, ok := params[setting.param]
+		if !ok || value == nil || value == "" {
+			continue
+		}
+		if err := setting.apply(value, &update); err != nil {
+			return err
+		}
+	}
+	if err := checkResources(update.Resources); err != nil {
+		return err
+	}
+	hostConfig.Resources = update.Resources
+
+	for _, param := range []string{"cap_add", "cap_drop"} {
+		capabilities := stringSlice(params, param)
+		for i, capability := range capabilities {
+			capability = strings.ToUpper(capability)
+			if capability != "ALL" && !capabilityPattern.MatchString(capability) {
+				return fmt.Errorf("invalid capability %q in %s", capabilities[i], param)
+			}
+			capabilities[i] = capability
+		}
+		if param == "cap_add" {
+			hostConfig.CapAdd = capabilities
+		} else {
+			hostConfig.CapDrop = capabilities
+		}
+	}
+
+	if readOnly, ok := params["read_only"].(bool); ok {
+		hostConfig.ReadonlyRootfs = readOnly
+	}
+
+	for _, spec := range stringSlice(params, "tmpfs") {
+		target, options, _ := strings.Cut(spec, ":")
+		if !path.IsAbs(target) {
+			return fmt.Errorf("invalid tmpfs %q: the path must be absolute", spec)
+		}
+		if hostConfig.Tmpfs == nil {
+			hostConfig.Tmpfs = make(map[string]string)
+		}
+		hostConfig.Tmpfs[path.Clean(target)] = options
+	}
+
+	limits, err := ulimits(params)
+	if err != nil {
+		return err
+	}
+	hostConfig.Ulimits = limits
+
+	for _, spec := range stringSlice(params, "extra_hosts") {
+		host, ip, ok := strings.Cut(spec, "=")
+		if !ok {
+			host, ip, ok = strings.Cut(spec, ":")
+		}
+		if !ok || host == "" || (ip != "host-gateway" && net.ParseIP(ip) == nil) {
+			return fmt.Errorf("invalid extra host %q: expected host:ip or host:host-gateway", spec)
+		}
+		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, host+":"+ip)
+	}
+
+	for _, server := range stringSlice(params, "dns") {
+		if net.ParseIP(server) == nil {
+			return fmt.Errorf("invalid DNS server %q: expected an IP address", server)
+		}
+		hostConfig.DNS = append(hostConfig.DNS, server)
+	}
+	hostConfig.DNSSearch = stringSlice(params, "dns_search")
+	hostConfig.DNSOptions = stringSlice(params, "dns_options")
+
+	for _, opt := range stringSlice(params, "security_opt") {
+		if err := validateSecurityOpt(opt); err != nil {
+			return err
+		}
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, opt)
+	}
+
+	if driver, ok := params["log_driver"].(string); ok && driver != "" {
+		hostConfig.LogConfig.Type = driver
+	}
+	hostConfig.LogConfig.Config = stringMap(params, "log_opts")
+	if hostConfig.LogConfig.Type == "none" && len(hostConfig.LogConfig.Config) > 0 {
+		return fmt.Errorf("log_opts cannot be used with the none log driver")
+	}
+
+	return nil
+}
+
+// checkResources rejects resource limits that contradict each other
+func checkResources(r container.Resources) error {
+	switch {
+	case r.NanoCPUs > 0 && (r.CPUQuota != 0 || r.CPUPeriod > 0):
+		return fmt.Errorf("cpus and cpu_quota/cpu_period are mutually exclusive")
+	case r.MemorySwap > 0 && r.Memory > 0 && r.MemorySwap < r.Memory:
+		return fmt.Errorf("memory_swap must be at least memory, as it includes it")
+	case r.MemoryReservation > 0 && r.Memory > 0 && r.MemoryReservation > r.Memory:
+		return fmt.Errorf("memory_reservation must not exceed memory")
+	}
+	return nil
+}
+
+// ulimits parses the ulimits parameter, an object of limit names to a
+// number or a "soft:hard" string
+func ulimits(params map[string]interface{}) ([]*container.Ulimit, error) {
+	limitMap, ok := params["ulimits"].(map[string]interface{})
+	if !ok || len(limitMap) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(limitMap))
+	for name := range limitMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]*container.Ulimit, 0, len(names))
+	for _, name := range names {
+		var value string
+		switch v := limitMap[name].(type) {
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("invalid ulimit %s: expected a number or a soft:hard string", name)
+		}
+		limit, err := units.ParseUlimit(name + "=" + value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ulimit %s: %w", name, err)
+		}
+		result = append(result, limit)
+	}
+	return result, nil
+}
+
+// validateSecurityOpt checks a security option. Only no-new-privileges and
+// label, apparmor, seccomp and systempaths options are accepted.
+func validateSecurityOpt(opt string) error {
+	key, value, hasValue := strings.Cut(opt, "=")
+	if !hasValue {
+		key, value, hasValue = strings.Cut(opt, ":")
+	}
+	switch {
+	case key == "no-new-privileges":
+		if hasValue && value != "true" && value != "false" {
+			return fmt.Errorf("invalid security_opt %q: no-new-privileges takes true or false", opt)
+		}
+	case !securityOptKeys[key] || value == "":
+		return fmt.Errorf("invalid security_opt %q: expected no-new-privileges or label, apparmor, seccomp or systempaths with a value", opt)
+	case key == "systempaths" && value != "unconfined":
+		return fmt.Errorf("invalid security_opt %q: systempaths only accepts unconfined", opt)
+	}
+	return nil
+}
+
+// checkNetworkModeConflicts rejects settings that a container sharing another
+// container's network namespace cannot have, as the daemon does
+func checkNetworkModeConflicts(config *container.Config, hostConfig *container.HostConfig) error {
+	if !hostConfig.NetworkMode.IsContainer() {
+		return nil
+	}
+	var conflicts []string
+	if config.Hostname != "" {
+		conflicts = append(conflicts, "hostname")
+	}
+	if len(hostConfig.DNS) > 0 || len(hostConfig.DNSSearch) > 0 || len(hostConfig.DNSOptions) > 0 {
+		conflicts = append(conflicts, "dns")
+	}
+	if len(hostConfig.ExtraHosts) > 0 {
+		conflicts = append(conflicts, "extra_hosts")
+	}
+	if len(hostConfig.PortBindings) > 0 {
+		conflicts = append(conflicts, "ports")
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%s cannot be used with network mode %s", strings.Join(conflicts, ", "), hostConfig.NetworkMode)
+	}
+	return nil
+}
+
+// joinedNetworkMode returns the network mode of the container whose network
+// namespace a container:<id> network mode joins, following chains of such
+// modes. It is empty for any other network mode.
+func (h *Handler) joinedNetworkMode(ctx context.Context, mode container.NetworkMode) (container.NetworkMode, error) {
+	if !mode.IsContainer() {
+		return "", nil
+	}
+
+	seen := make(map[string]bool)
+	for mode.IsContainer() {
+		containerID := mode.ConnectedContainer()
+		if seen[containerID] {
+			return "", fmt.Errorf("network mode %s forms a cycle", mode)
+		}
+		seen[containerID] = true
+
+		info, err := h.dockerClient.InspectContainer(ctx, containerID)
+		if err != nil {
+			return "", fmt.Errorf("failed to inspect container: %w", err)
+		}
+		if info.ContainerJSONBase == nil || info.HostConfig == nil {
+			return "", nil
+		}
+		mode = info.HostConfig.NetworkMode
+	}
+	return mode, nil
+}
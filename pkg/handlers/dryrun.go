@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/coolbit-in/docker-mcp/pkg/docker"
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-units"
+)
+
+// anonymousVolumePattern matches the generated names of anonymous volumes
+var anonymousVolumePattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// dryRun reports whether a mutating call should only be planned, either
+// because the server runs in dry-run mode or because the call asks for it
+func (h *Handler) dryRun(params map[string]interface{}) bool {
+	if h.options.DryRun {
+		return true
+	}
+	dryRun, _ := params["dry_run"].(bool)
+	return dryRun
+}
+
+// planContainerAction validates a container lifecycle action such as a start,
+// stop, kill or remove against the container's current state and describes
+// what the action would do
+func (h *Handler) planContainerAction(ctx context.Context, containerID, action string, params map[string]interface{}) (*models.DryRunResponse, error) {
+	info, err := h.dockerClient.InspectContainer(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	name := strings.TrimPrefix(info.Name, "/")
+	plan := &models.DryRunResponse{
+		DryRun: true,
+		Action: action,
+		ID:     info.ID,
+		Name:   name,
+		State:  info.State.Status,
+	}
+	running := info.State.Running
+
+	timeout := 10
+	if timeoutVal, ok := params["timeout"].(float64); ok {
+		timeout = int(timeoutVal)
+	}
+	stopSignal := "SIGTERM"
+	if info.Config != nil && info.Config.StopSignal != "" {
+		stopSignal = info.Config.StopSignal
+	}
+	stopStep := fmt.Sprintf("send %s to container %s and kill it after %d seconds if it is still running", stopSignal, name, timeout)
+
+	switch action {
+	case "start":
+		if running {
+			plan.Plan = append(plan.Plan, fmt.Sprintf("nothing to do: container %s is already running", name))
+		} else {
+			plan.Plan = append(plan.Plan, fmt.Sprintf("start container %s", name))
+		}
+	case "stop":
+		if !running {
+			plan.Plan = append(plan.Plan, fmt.Sprintf("nothing to do: container %s is not running", name))
+			break
+		}
+		plan.Plan = append(plan.Plan, stopStep)
+		if info.HostConfig != nil && info.HostConfig.AutoRemove {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("container %s has auto_remove set and will be removed once it stops", name))
+		}
+	case "restart":
+		if running {
+			plan.Plan = append(plan.Plan, stopStep)
+		}
+		plan.Plan = append(plan.Plan, fmt.Sprintf("start container %s", name))
+	case "remove":
+		force, _ := params["force"].(bool)
+		removeVolumes, _ := params["volumes"].(bool)
+		if running && !force {
+			return nil, fmt.Errorf("cannot remove container %s: it is running; stop it first or set force", name)
+		}
+		if running {
+			plan.Plan = append(plan.Plan, fmt.Sprintf("kill running container %s", name))
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("container %s is running and will be killed without a graceful stop", name))
+		}
+		plan.Plan = append(plan.Plan, fmt.Sprintf("remove container %s", name))
+		for _, m := range info.Mounts {
+			if m.Type != mount.TypeVolume {
+				continue
+			}
+			switch {
+			case removeVolumes && anonymousVolumePattern.MatchString(m.Name):
+				plan.Plan = append(plan.Plan, fmt.Sprintf("remove anonymous volume %s", m.Name))
+			case anonymousVolumePattern.MatchString(m.Name):
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("anonymous volume %s is kept and left unused; set volumes to remove it", m.Name))
+			}
+		}
+	case "pause":
+		switch {
+		case !running:
+			return nil, fmt.Errorf("cannot pause container %s: it is not running", name)
+		case info.State.Paused:
+			return nil, fmt.Errorf("cannot pause container %s: it is already paused", name)
+		}
+		plan.Plan = append(plan.Plan, fmt.Sprintf("freeze the processes of container %s", name))
+	case "unpause":
+		if !info.State.Paused {
+			return nil, fmt.Errorf("cannot unpause container %s: it is not paused", name)
+		}
+		plan.Plan = append(plan.Plan, fmt.Sprintf("resume the processes of container %s", name))
+	case "kill":
+		signalVal, _ := params["signal"].(string)
+		signal, err := normalizeSignal(signalVal)
+		if err != nil {
+			return nil, err
+		}
+		if !running {
+			return nil, fmt.Errorf("cannot kill container %s: it is not running", name)
+		}
+		plan.Plan = append(plan.Plan, fmt.Sprintf("send %s to the main process of container %s", signal, name))
+		if signal == "SIGKILL" || signal == "9" {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("container %s will be stopped without a graceful shutdown", name))
+		}
+	case "rename":
+		newName, _ := params["new_name"].(string)
+		newName = strings.TrimPrefix(newName, "/")
+		existing, err := h.dockerClient.InspectContainer(ctx, newName)
+		switch {
+		case err == nil && existing.ID != info.ID:
+			return nil, fmt.Errorf("container name %s is already in use by container %s", newName, shortID(existing.ID))
+		case err != nil && !errdefs.IsNotFound(err):
+			return nil, fmt.Errorf("failed to inspect container: %w", err)
+		}
+		plan.Plan = append(plan.Plan, fmt.Sprintf("rename container %s to %s", name, newName))
+	}
+
+	return plan, nil
+}
+
+// planCreateContainer validates a container creation against the daemon: the
+// image must be present locally and the name must be free
+func (h *Handler) planCreateContainer(ctx context.Context, name string, config *container.Config, hostConfig *container.HostConfig, networks []string) (*models.DryRunResponse, error) {
+	img, err := h.dockerClient.InspectImage(ctx, config.Image)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("image %s is not present locally; pull it first", config.Image)
+		}
+		return nil, fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	existing, err := h.dockerClient.InspectContainer(ctx, name)
+	switch {
+	case err == nil:
+		return nil, fmt.Errorf("container name %s is already in use by container %s", name, shortID(existing.ID))
+	case !errdefs.IsNotFound(err):
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	plan := &models.DryRunResponse{
+		DryRun: true,
+		Action: "create",
+		Name:   name,
+		Plan:   []string{fmt.Sprintf("create container %s from image %s (%s)", name, config.Image, shortID(img.ID))},
+	}
+
+	ports := make([]string, 0, len(hostConfig.PortBindings))
+	for port, bindings := range hostConfig.PortBindings {
+		for _, b := range bindings {
+			ports = append(ports, fmt.Sprintf("publish %s on host port %s", port, b.HostPort))
+		}
+	}
+	sort.Strings(ports)
+	plan.Plan = append(plan.Plan, ports...)
+
+	for _, bind := range hostConfig.Binds {
+		plan.Plan = append(plan.Plan, bindStep(bind))
+	}
+	for _, m := range hostConfig.Mounts {
+		step := fmt.Sprintf("mount %s %s at %s", m.Type, m.Source, m.Target)
+		if m.Type == mount.TypeTmpfs {
+			step = fmt.Sprintf("mount tmpfs at %s", m.Target)
+		}
+		if m.ReadOnly {
+			step += " (read-only)"
+		}
+		plan.Plan = append(plan.Plan, step)
+	}
+
+	if hostConfig.NetworkMode != "" {
+		plan.Plan = append(plan.Plan, fmt.Sprintf("use network mode %s", hostConfig.NetworkMode))
+	}
+	for _, network := range networks {
+		plan.Plan = append(plan.Plan, fmt.Sprintf("attach to network %s", network))
+	}
+
+	return plan, nil
+}
+
+// planRemoveImage validates an image removal against the image's tags and
+// the containers using it
+func (h *Handler) planRemoveImage(ctx context.Context, ref string, force bool) (*models.DryRunResponse, error) {
+	img, err := h.dockerClient.InspectImage(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	plan := &models.DryRunResponse{
+		DryRun: true,
+		Action: "remove",
+		ID:     img.ID,
+		Name:   ref,
+	}
+
+	// Removing one of several tags only untags the image
+	if tag := familiarTag(ref); tag != "" && len(img.RepoTags) > 1 {
+		for _, t := range img.RepoTags {
+			if familiarTag(t) == tag {
+				plan.Plan = append(plan.Plan, fmt.Sprintf("untag %s; image %s keeps its other tags", t, shortID(img.ID)))
+				return plan, nil
+			}
+		}
+	}
+
+	containers, err := h.dockerClient.ListContainers(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	var running, stopped []string
+	for _, c := range containers {
+		if c.ImageID != img.ID {
+			continue
+		}
+		name := shortID(c.ID)
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		if c.State == "running" {
+			running = append(running, name)
+		} else {
+			stopped = append(stopped, name)
+		}
+	}
+	switch {
+	case len(running) > 0:
+		return nil, fmt.Errorf("cannot remove image %s: it is used by running containers %s", ref, strings.Join(running, ", "))
+	case len(stopped) > 0 && !force:
+		return nil, fmt.Errorf("cannot remove image %s: it is used by stopped containers %s; remove them first or set force", ref, strings.Join(stopped, ", "))
+	case len(stopped) > 0:
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("containers %s would be left referring to a removed image", strings.Join(stopped, ", ")))
+	}
+
+	for _, t := range img.RepoTags {
+		plan.Plan = append(plan.Plan, fmt.Sprintf("untag %s", t))
+	}
+	plan.Plan = append(plan.Plan, fmt.Sprintf("delete image %s (%s)", shortID(img.ID), units.HumanSize(float64(img.Size))))
+	return plan, nil
+}
+
+// planBuildImage describes a build whose context has been prepared but not sent
+func (h *Handler) planBuildImage(ctx context.Context, archive *docker.BuildArchive, options types.ImageBuildOptions) *models.DryRunResponse {
+	// Release the tar stream of a directory context, which is never read
+	if closer, ok := archive.Context.(io.Closer); ok {
+		closer.Close()
+	}
+
+	plan := &models.DryRunResponse{
+		DryRun: true,
+		Action: "build",
+		Name:   options.Tags[0],
+	}
+
+	if archive.RemoteURL != "" {
+		plan.Plan = append(plan.Plan, fmt.Sprintf("have the daemon fetch the build context from %s", archive.RemoteURL))
+	} else {
+		plan.Plan = append(plan.Plan, fmt.Sprintf("send a build context of %d files (%s), %d excluded by .dockerignore",
+			archive.Files, units.HumanSize(float64(archive.Size)), archive.Ignored))
+	}
+
+	build := fmt.Sprintf("build %s", options.Dockerfile)
+	if options.Target != "" {
+		build += fmt.Sprintf(" up to stage %s", options.Target)
+	}
+	if options.Platform != "" {
+		build += fmt.Sprintf(" for %s", options.Platform)
+	}
+	if options.NoCache {
+		build += " without the build cache"
+	}
+	plan.Plan = append(plan.Plan, build)
+
+	for _, tag := range options.Tags {
+		plan.Plan = append(plan.Plan, fmt.Sprintf("tag the image as %s", tag))
+		if existing, err := h.dockerClient.InspectImage(ctx, tag); err == nil {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("tag %s currently points to image %s and would be moved", tag, shortID(existing.ID)))
+		}
+	}
+
+	return plan
+}
+
+// bindStep describes a "source:target[:options]" volume mapping as a plan step
+func bindStep(bind string) string {
+	parts := strings.SplitN(bind, ":", 3)
+	if len(parts) == 1 {
+		return fmt.Sprintf("mount anonymous volume at %s", bind)
+	}
+
+	mountType := mount.TypeVolume
+	if strings.HasPrefix(parts[0], "/") {
+		mountType = mount.TypeBind
+	}
+	step := fmt.Sprintf("mount %s %s at %s", mountType, parts[0], parts[1])
+	if len(parts) == 3 {
+		for _, opt := range strings.Split(parts[2], ",") {
+			if opt == "ro" {
+				step += " (read-only)"
+			}
+		}
+	}
+	return step
+}
+
+// familiarTag returns the short name:tag form of a tagged image reference, or "" for IDs and digests
+func familiarTag(ref string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ""
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return ""
+	}
+	return reference.FamiliarString(reference.TagNameOnly(named))
+}
+
+// shortID returns the 12 character form of an ID
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -4,34 +4,58 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"strings"
 	"time"
 
 	"github.com/coolbit-in/docker-mcp/pkg/docker"
 	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/coolbit-in/docker-mcp/pkg/policy"
+	"github.com/coolbit-in/docker-mcp/pkg/redact"
 	"github.com/docker/docker/api/types/container"
-	"github.com/docker/go-connections/nat"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // Handler represents a Docker MCP request handler
 type Handler struct {
-	dockerClient *docker.Client
-	progressCh   chan models.ProgressEvent
+	dockerClient docker.API
+	options      Options
 }
 
+// Options configures limits applied by the handlers. The zero value uses the defaults.
+type Options struct {
+	MaxBuildContextSize int64            // Largest local build context uploaded to the daemon, in bytes (default 512MiB)
+	ScopeLabel          string           // "key=value" label stamped on created containers and required by the other container tools; "{principal}" expands to the caller's name
+	Policy              *policy.Policy   // Safety policy for create_container, update_container, create_volume, exec_command and build_image; nil allows everything
+	DryRun              bool             // Plan every call that accepts dry_run instead of performing it; the server refuses other mutating tools
+	Redactor            *redact.Redactor // Masks secrets in inspect, logs, exec and process output (default redact.Default())
+}
+
+// DefaultMaxBuildContextSize is the build context limit used when Options leaves it unset
+const DefaultMaxBuildContextSize = 512 << 20
+
 // NewHandler creates and initializes a new handler
-func NewHandler(dockerSocket string) (*Handler, error) {
+func NewHandler(dockerSocket string, options Options) (*Handler, error) {
 	client, err := docker.NewClient(dockerSocket)
 	if err != nil {
 		return nil, err
 	}
 
+	return NewHandlerWithClient(client, options), nil
+}
+
+// NewHandlerWithClient creates a handler that performs Docker operations through the given API
+func NewHandlerWithClient(client docker.API, options Options) *Handler {
+	if options.MaxBuildContextSize <= 0 {
+		options.MaxBuildContextSize = DefaultMaxBuildContextSize
+	}
+	if options.Redactor == nil {
+		options.Redactor = redact.Default()
+	}
+
 	return &Handler{
 		dockerClient: client,
-		progressCh:   make(chan models.ProgressEvent, 100),
-	}, nil
+		options:      options,
+	}
 }
 
 // formatResponse formats the response in standard JSON format
@@ -57,6 +81,10 @@ func (h *Handler) formatResponse(data interface{}) (*mcp.CallToolResult, error)
 		response.Count = len(v)
 	case []models.SearchResult:
 		response.Count = len(v)
+	case []models.NetworkInfo:
+		response.Count = len(v)
+	case []models.VolumeInfo:
+		response.Count = len(v)
 	case []interface{}:
 		response.Count = len(v)
 	}
@@ -111,6 +139,10 @@ func (h *Handler) HandleListContainers(ctx context.Context, request mcp.CallTool
 
 	var result []models.ContainerInfo
 	for _, c := range containers {
+		if !h.inScope(ctx, c.Labels) {
+			continue
+		}
+
 		containerInfo := models.ContainerInfo{
 			ID:      c.ID,
 			Names:   c.Names,
@@ -145,61 +177,71 @@ func (h *Handler) HandleExecCommand(ctx context.Context, request mcp.CallToolReq
 	if !ok || containerID == "" {
 		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
 	}
-
-	command, ok := params["command"].(string)
-	if !ok || command == "" {
-		return h.formatErrorResponse(fmt.Errorf("command is required"))
-	}
-
-	output, err := h.dockerClient.ExecCommand(ctx, containerID, command)
-	if err != nil {
+	if err := h.checkScope(ctx, containerID); err != nil {
 		return h.formatErrorResponse(err)
 	}
 
-	return h.formatResponse(models.CommandResponse{
-		ContainerID: containerID,
-		Command:     command,
-		Output:      output,
-	})
-}
-
-// HandlePullImage handles image pull requests with progress tracking
-func (h *Handler) HandlePullImage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	params := request.Params.Arguments
-	imageName, ok := params["image_name"].(string)
-	if !ok || imageName == "" {
-		return h.formatErrorResponse(fmt.Errorf("image_name is required"))
+	// A shell command string or an argv array, but not both
+	command, _ := params["command"].(string)
+	argv := stringSlice(params, "argv")
+	if command == "" && len(argv) == 0 {
+		return h.formatErrorResponse(fmt.Errorf("command or argv is required"))
 	}
-
-	// Call Docker API to pull image
-	reader, err := h.dockerClient.PullImage(ctx, imageName)
-	if err != nil {
-		return h.formatErrorResponse(fmt.Errorf("failed to pull image: %w", err))
+	if command != "" && len(argv) > 0 {
+		return h.formatErrorResponse(fmt.Errorf("command and argv are mutually exclusive"))
 	}
-	defer reader.Close()
 
-	// Handle streaming response
-	decoder := json.NewDecoder(reader)
-	for {
-		var event models.ProgressEvent
-		if err := decoder.Decode(&event); err != nil {
-			if err == io.EOF {
-				break
-			}
-			return h.formatErrorResponse(fmt.Errorf("failed to decode progress event: %w", err))
+	opts := docker.ExecOptions{
+		Cmd: argv,
+		Env: stringSlice(params, "env"),
+	}
+	if command != "" {
+		opts.Cmd = []string{"sh", "-c", command}
+	} else {
+		command = strings.Join(argv, " ")
+	}
+	if user, ok := params["user"].(string); ok {
+		opts.User = user
+	}
+	if workDir, ok := params["working_dir"].(string); ok {
+		opts.WorkingDir = workDir
+	}
+	if privileged, ok := params["privileged"].(bool); ok {
+		opts.Privileged = privileged
+	}
+	if stdin, ok := params["stdin"].(string); ok {
+		opts.Stdin = []byte(stdin)
+	}
+	if timeoutVal, ok := params["timeout"].(float64); ok {
+		if timeoutVal < 0 {
+			return h.formatErrorResponse(fmt.Errorf("timeout must not be negative"))
 		}
+		opts.Timeout = time.Duration(timeoutVal * float64(time.Second))
+	}
 
-		// Send progress event
-		h.progressCh <- event
+	info, err := h.dockerClient.InspectContainer(ctx, containerID)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to inspect container: %w", err))
+	}
+	if err := h.options.Policy.CheckExec(info, opts.Privileged); err != nil {
+		return h.formatErrorResponse(err)
 	}
 
-	result := models.PullProgressResponse{
-		ImageName: imageName,
-		Status:    "success",
-		Complete:  true,
+	result, err := h.dockerClient.ExecCommand(ctx, containerID, opts)
+	if err != nil {
+		return h.formatErrorResponse(err)
 	}
 
-	return h.formatResponse(result)
+	redactor := h.redactor(params)
+	return h.formatResponse(models.CommandResponse{
+		ContainerID: containerID,
+		Command:     redactor.String(command),
+		Stdout:      redactor.String(result.Stdout),
+		Stderr:      redactor.String(result.Stderr),
+		ExitCode:    result.ExitCode,
+		TimedOut:    result.TimedOut,
+		Killed:      result.Killed,
+	})
 }
 
 // HandleListImages handles image listing requests
@@ -313,78 +355,63 @@ func (h *Handler) HandleCreateContainer(ctx context.Context, request mcp.CallToo
 		config.WorkingDir = workingDir
 	}
 
+	// Optional entrypoint, user, hostname, labels, stop settings and healthcheck
+	if err := applyContainerOptions(params, config); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	// Stamp the scope label so the container can be managed afterwards. It
+	// overrides a label of the same key given by the caller.
+	if key, value, ok := h.scopeLabel(ctx); ok {
+		if config.Labels == nil {
+			config.Labels = make(map[string]string)
+		}
+		config.Labels[key] = value
+	}
+
 	// Host configuration
 	hostConfig := &container.HostConfig{}
 
 	// Optional port mappings
-	if portMapObj, ok := params["ports"].(map[string]interface{}); ok && len(portMapObj) > 0 {
-		portBindings := nat.PortMap{}
-		exposedPorts := nat.PortSet{}
-
-		for portMapping := range portMapObj {
-			parts := strings.Split(portMapping, ":")
-			if len(parts) != 2 {
-				continue
-			}
-
-			hostPort := parts[0]
-			containerPortProto := parts[1]
-
-			// Ensure format is port/protocol
-			portParts := strings.Split(containerPortProto, "/")
-			if len(portParts) != 2 {
-				// If no protocol is specified, default to TCP
-				containerPortProto = containerPortProto + "/tcp"
-				portParts = []string{containerPortProto, "tcp"}
-			}
-
-			containerPort, err := nat.NewPort(portParts[1], portParts[0])
-			if err != nil {
-				continue
-			}
-
-			portBindings[containerPort] = []nat.PortBinding{
-				{
-					HostIP:   "0.0.0.0",
-					HostPort: hostPort,
-				},
-			}
-
-			exposedPorts[containerPort] = struct{}{}
-		}
-
-		hostConfig.PortBindings = portBindings
-		config.ExposedPorts = exposedPorts
+	exposedPorts, bindings, err := portBindings(params)
+	if err != nil {
+		return h.formatErrorResponse(err)
 	}
+	config.ExposedPorts = exposedPorts
+	hostConfig.PortBindings = bindings
 
-	// Optional volume mappings
-	if volumesArray, ok := params["volumes"].([]interface{}); ok && len(volumesArray) > 0 {
-		volumes := make([]string, len(volumesArray))
-		for i, v := range volumesArray {
-			if s, ok := v.(string); ok {
-				volumes[i] = s
-			}
-		}
-		hostConfig.Binds = volumes
+	// Optional volume mappings, passed to the daemon unchanged as binds
+	hostConfig.Binds = stringSlice(params, "volumes")
+
+	// Optional structured mounts
+	containerMounts, err := mounts(params)
+	if err != nil {
+		return h.formatErrorResponse(err)
 	}
+	hostConfig.Mounts = containerMounts
 
 	// Optional network mode
 	if networkMode, ok := params["network_mode"].(string); ok && networkMode != "" {
 		hostConfig.NetworkMode = container.NetworkMode(networkMode)
 	}
 
+	// Optional networks to attach at creation time
+	netConfig, networkNames, err := networkingConfig(params)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+	if len(networkNames) > 0 && hostConfig.NetworkMode == "" {
+		// Use the first network as the primary one, as `docker run --network` does
+		hostConfig.NetworkMode = container.NetworkMode(networkNames[0])
+	}
+
 	// Optional restart policy
-	if restartPolicy, ok := params["restart_policy"].(string); ok && restartPolicy != "" {
-		switch restartPolicy {
-		case "no":
-			hostConfig.RestartPolicy = container.RestartPolicy{Name: "no"}
-		case "always":
-			hostConfig.RestartPolicy = container.RestartPolicy{Name: "always"}
-		case "unless-stopped":
-			hostConfig.RestartPolicy = container.RestartPolicy{Name: "unless-stopped"}
-		case "on-failure":
-			hostConfig.RestartPolicy = container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 3}
-		}
+	restart, hasRestart, err := restartPolicy(params)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+	if hasRestart {
+		hostConfig.RestartPolicy = restart
 	}
 
 	// Optional auto removal
@@ -392,8 +419,35 @@ func (h *Handler) HandleCreateContainer(ctx context.Context, request mcp.CallToo
 		hostConfig.AutoRemove = autoRemove
 	}
 
+	// Optional resource limits, capabilities, filesystem, DNS and logging settings
+	if err := applyHostOptions(params, hostConfig); err != nil {
+		return h.formatErrorResponse(err)
+	}
+	if err := checkNetworkModeConflicts(config, hostConfig); err != nil {
+		return h.formatErrorResponse(err)
+	}
+	if err := h.checkReferencedScope(ctx, hostConfig); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	joined, err := h.joinedNetworkMode(ctx, hostConfig.NetworkMode)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+	if err := h.options.Policy.CheckCreate(config, hostConfig, joined); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	if h.dryRun(params) {
+		plan, err := h.planCreateContainer(ctx, containerName, config, hostConfig, networkNames)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
+
 	// Create container
-	resp, err := h.dockerClient.CreateContainer(ctx, config, hostConfig, containerName)
+	resp, err := h.dockerClient.CreateContainer(ctx, config, hostConfig, netConfig, containerName)
 	if err != nil {
 		return h.formatErrorResponse(fmt.Errorf("failed to create container: %w", err))
 	}
@@ -412,6 +466,17 @@ func (h *Handler) HandleStartContainer(ctx context.Context, request mcp.CallTool
 	if !ok || containerID == "" {
 		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
 	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	if h.dryRun(params) {
+		plan, err := h.planContainerAction(ctx, containerID, "start", params)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
 
 	err := h.dockerClient.StartContainer(ctx, containerID)
 	if err != nil {
@@ -433,12 +498,23 @@ func (h *Handler) HandleStopContainer(ctx context.Context, request mcp.CallToolR
 	if !ok || containerID == "" {
 		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
 	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
 
 	var timeoutSecs int = 10
 	if timeoutVal, ok := params["timeout"].(float64); ok {
 		timeoutSecs = int(timeoutVal)
 	}
 
+	if h.dryRun(params) {
+		plan, err := h.planContainerAction(ctx, containerID, "stop", params)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
+
 	err := h.dockerClient.StopContainer(ctx, containerID, &timeoutSecs)
 	if err != nil {
 		return h.formatErrorResponse(fmt.Errorf("failed to stop container: %w", err))
@@ -459,12 +535,23 @@ func (h *Handler) HandleRestartContainer(ctx context.Context, request mcp.CallTo
 	if !ok || containerID == "" {
 		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
 	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
 
 	var timeoutSecs int = 10
 	if timeoutVal, ok := params["timeout"].(float64); ok {
 		timeoutSecs = int(timeoutVal)
 	}
 
+	if h.dryRun(params) {
+		plan, err := h.planContainerAction(ctx, containerID, "restart", params)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
+
 	err := h.dockerClient.RestartContainer(ctx, containerID, &timeoutSecs)
 	if err != nil {
 		return h.formatErrorResponse(fmt.Errorf("failed to restart container: %w", err))
@@ -485,6 +572,9 @@ func (h *Handler) HandleRemoveContainer(ctx context.Context, request mcp.CallToo
 	if !ok || containerID == "" {
 		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
 	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
 
 	force := false
 	if forceVal, ok := params["force"].(bool); ok {
@@ -496,6 +586,14 @@ func (h *Handler) HandleRemoveContainer(ctx context.Context, request mcp.CallToo
 		removeVolumes = volumesVal
 	}
 
+	if h.dryRun(params) {
+		plan, err := h.planContainerAction(ctx, containerID, "remove", params)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
+
 	err := h.dockerClient.RemoveContainer(ctx, containerID, force, removeVolumes)
 	if err != nil {
 		return h.formatErrorResponse(fmt.Errorf("failed to remove container: %w", err))
@@ -522,6 +620,14 @@ func (h *Handler) HandleRemoveImage(ctx context.Context, request mcp.CallToolReq
 		force = forceVal
 	}
 
+	if h.dryRun(params) {
+		plan, err := h.planRemoveImage(ctx, imageID, force)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
+
 	response, err := h.dockerClient.RemoveImage(ctx, imageID, force)
 	if err != nil {
 		return h.formatErrorResponse(fmt.Errorf("failed to remove image: %w", err))
@@ -544,51 +650,6 @@ func (h *Handler) HandleRemoveImage(ctx context.Context, request mcp.CallToolReq
 	return h.formatResponse(result)
 }
 
-// HandleContainerLogs handles container logs requests
-func (h *Handler) HandleContainerLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	params := request.Params.Arguments
-
-	containerID, ok := params["container_id"].(string)
-	if !ok || containerID == "" {
-		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
-	}
-
-	follow := false
-	if followVal, ok := params["follow"].(bool); ok {
-		follow = followVal
-	}
-
-	timestamps := false
-	if tsVal, ok := params["timestamps"].(bool); ok {
-		timestamps = tsVal
-	}
-
-	tail := "100"
-	if tailVal, ok := params["tail"].(float64); ok {
-		if tailVal < 0 {
-			tail = "all"
-		} else {
-			tail = fmt.Sprintf("%d", int(tailVal))
-		}
-	}
-
-	reader, err := h.dockerClient.ContainerLogs(ctx, containerID, follow, timestamps, tail)
-	if err != nil {
-		return h.formatErrorResponse(fmt.Errorf("failed to get container logs: %w", err))
-	}
-	defer reader.Close()
-
-	logs, err := io.ReadAll(reader)
-	if err != nil {
-		return h.formatErrorResponse(fmt.Errorf("failed to read logs: %w", err))
-	}
-
-	return h.formatResponse(models.LogsResponse{
-		ContainerID: containerID,
-		Logs:        string(logs),
-	})
-}
-
 // HandleInspectContainer handles container inspection requests
 func (h *Handler) HandleInspectContainer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	params := request.Params.Arguments
@@ -597,6 +658,9 @@ func (h *Handler) HandleInspectContainer(ctx context.Context, request mcp.CallTo
 	if !ok || containerID == "" {
 		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
 	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
 
 	containerInfo, err := h.dockerClient.InspectContainer(ctx, containerID)
 	if err != nil {
@@ -608,6 +672,10 @@ func (h *Handler) HandleInspectContainer(ctx context.Context, request mcp.CallTo
 	if err != nil {
 		return h.formatErrorResponse(fmt.Errorf("failed to serialize container information: %w", err))
 	}
+	details, err = h.redactor(params).JSON(details)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to redact container information: %w", err))
+	}
 
 	return h.formatResponse(models.InspectResponse{
 		ID:      containerID,
@@ -635,6 +703,10 @@ func (h *Handler) HandleInspectImage(ctx context.Context, request mcp.CallToolRe
 	if err != nil {
 		return h.formatErrorResponse(fmt.Errorf("failed to serialize image information: %w", err))
 	}
+	details, err = h.redactor(params).JSON(details)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to redact image information: %w", err))
+	}
 
 	return h.formatResponse(models.InspectResponse{
 		ID:      imageID,
@@ -642,69 +714,3 @@ func (h *Handler) HandleInspectImage(ctx context.Context, request mcp.CallToolRe
 		Details: details,
 	})
 }
-
-// HandleBuildImage handles image build requests
-func (h *Handler) HandleBuildImage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	params := request.Params.Arguments
-
-	contextPath, ok := params["context_path"].(string)
-	if !ok || contextPath == "" {
-		return h.formatErrorResponse(fmt.Errorf("context_path is required"))
-	}
-
-	dockerfileName := "Dockerfile"
-	if df, ok := params["dockerfile"].(string); ok && df != "" {
-		dockerfileName = df
-	}
-
-	tag, ok := params["tag"].(string)
-	if !ok || tag == "" {
-		return h.formatErrorResponse(fmt.Errorf("tag is required"))
-	}
-
-	noCache := false
-	if noCacheVal, ok := params["no_cache"].(bool); ok {
-		noCache = noCacheVal
-	}
-
-	pull := false
-	if pullVal, ok := params["pull"].(bool); ok {
-		pull = pullVal
-	}
-
-	resp, err := h.dockerClient.BuildImage(ctx, contextPath, dockerfileName, []string{tag}, noCache, pull)
-	if err != nil {
-		return h.formatErrorResponse(fmt.Errorf("failed to build image: %w", err))
-	}
-	defer resp.Body.Close()
-
-	// Read build output
-	buildOutput, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return h.formatErrorResponse(fmt.Errorf("failed to read build output: %w", err))
-	}
-
-	// Find successful build message
-	outputStr := string(buildOutput)
-	if !strings.Contains(outputStr, "Successfully built") {
-		return h.formatResponse(models.BuildImageResponse{
-			Success: false,
-			Error:   "build failed, please check build output",
-		})
-	}
-
-	// Extract image ID (if available)
-	imageID := ""
-	if idIndex := strings.Index(outputStr, "Successfully built "); idIndex > 0 {
-		idPart := outputStr[idIndex+18:]
-		if newlineIndex := strings.Index(idPart, "\n"); newlineIndex > 0 {
-			imageID = strings.TrimSpace(idPart[:newlineIndex])
-		}
-	}
-
-	return h.formatResponse(models.BuildImageResponse{
-		Success: true,
-		ImageID: imageID,
-		Tags:    []string{tag},
-	})
-}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultDiffMaxEntries caps the returned filesystem changes unless the caller overrides it
+const defaultDiffMaxEntries = 1000
+
+// HandleContainerTop handles container process listing requests
+func (h *Handler) HandleContainerTop(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	// ps runs on the host, so the image needs no ps of its own
+	psArgs, _ := params["ps_args"].(string)
+	top, err := h.dockerClient.ContainerTop(ctx, containerID, strings.Fields(psArgs))
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to list container processes: %w", err))
+	}
+
+	response := &models.ContainerTopResponse{
+		ContainerID: containerID,
+		Titles:      top.Titles,
+		Processes:   make([]map[string]string, 0, len(top.Processes)),
+	}
+	// Command lines often carry secrets such as --password=...
+	redactor := h.redactor(params)
+	for _, process := range top.Processes {
+		row := processRow(top.Titles, process)
+		for title, value := range row {
+			row[title] = redactor.String(value)
+		}
+		response.Processes = append(response.Processes, row)
+	}
+
+	return h.formatResponse(response)
+}
+
+// processRow keys a ps output row by its column titles. Values beyond the
+// last title, such as the words of an unsplit command line, are joined into
+// the last column.
+func processRow(titles, values []string) map[string]string {
+	row := make(map[string]string, len(titles))
+	for i, title := range titles {
+		switch {
+		case i >= len(values):
+			row[title] = ""
+		case i == len(titles)-1:
+			row[title] = strings.Join(values[i:], " ")
+		default:
+			row[title] = values[i]
+		}
+	}
+	return row
+}
+
+// HandleContainerDiff handles container filesystem change requests
+func (h *Handler) HandleContainerDiff(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	root := "/"
+	if pathVal, ok := params["path"].(string); ok && pathVal != "" {
+		if !path.IsAbs(pathVal) {
+			return h.formatErrorResponse(fmt.Errorf("path must be absolute"))
+		}
+		root = path.Clean(pathVal)
+	}
+	maxEntries := defaultDiffMaxEntries
+	if maxVal, ok := params["max_entries"].(float64); ok {
+		maxEntries = int(maxVal)
+	}
+
+	changes, err := h.dockerClient.ContainerDiff(ctx, containerID)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to get container changes: %w", err))
+	}
+
+	// Keep the changes under the path, in path order so truncation is stable
+	matched := changes[:0]
+	for _, change := range changes {
+		if root == "/" || change.Path == root || strings.HasPrefix(change.Path, root+"/") {
+			matched = append(matched, change)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Path < matched[j].Path })
+
+	response := &models.ContainerDiffResponse{
+		ContainerID: containerID,
+		Added:       []string{},
+		Changed:     []string{},
+		Deleted:     []string{},
+		Total:       len(matched),
+	}
+	if maxEntries > 0 && len(matched) > maxEntries {
+		matched = matched[:maxEntries]
+		response.Truncated = true
+	}
+	for _, change := range matched {
+		switch change.Kind {
+		case container.ChangeAdd:
+			response.Added = append(response.Added, change.Path)
+		case container.ChangeModify:
+			response.Changed = append(response.Changed, change.Path)
+		case container.ChangeDelete:
+			response.Deleted = append(response.Deleted, change.Path)
+		}
+	}
+
+	return h.formatResponse(response)
+}
+
+// HandleContainerPort handles container port binding requests
+func (h *Handler) HandleContainerPort(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	// A port without a protocol matches both tcp and udp
+	portFilter, _ := params["port"].(string)
+	if portFilter != "" {
+		proto, port := nat.SplitProtoPort(portFilter)
+		if _, err := nat.NewPort(proto, port); err != nil {
+			return h.formatErrorResponse(fmt.Errorf("invalid port %q: %w", portFilter, err))
+		}
+	}
+
+	ports, err := h.dockerClient.ContainerPorts(ctx, containerID)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to get container ports: %w", err))
+	}
+
+	response := &models.ContainerPortResponse{
+		ContainerID: containerID,
+		Ports:       []models.ExposedPort{},
+	}
+	for port, bindings := range ports {
+		if portFilter != "" && string(port) != portFilter && port.Port() != portFilter {
+			continue
+		}
+		exposed := models.ExposedPort{Port: string(port), Bindings: []models.HostBinding{}}
+		for _, b := range bindings {
+			exposed.Bindings = append(exposed.Bindings, models.HostBinding{HostIP: b.HostIP, HostPort: b.HostPort})
+		}
+		response.Ports = append(response.Ports, exposed)
+	}
+	if portFilter != "" && len(response.Ports) == 0 {
+		return h.formatErrorResponse(fmt.Errorf("container does not expose port %s", portFilter))
+	}
+
+	// Sort numerically by port, then by protocol
+	sort.Slice(response.Ports, func(i, j int) bool {
+		a, b := nat.Port(response.Ports[i].Port), nat.Port(response.Ports[j].Port)
+		if a.Int() != b.Int() {
+			return a.Int() < b.Int()
+		}
+		return a.Proto() < b.Proto()
+	})
+
+	return h.formatResponse(response)
+}
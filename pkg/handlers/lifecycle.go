@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/docker/docker/api/types/container"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Wait timeout bounds
+const (
+	defaultWaitTimeout = time.Minute
+	maxWaitTimeout     = 10 * time.Minute
+)
+
+// signalNames are the signal names accepted by kill_container, besides real-time signals
+var signalNames = map[string]bool{
+	"SIGHUP": true, "SIGINT": true, "SIGQUIT": true, "SIGILL": true, "SIGTRAP": true, "SIGABRT": true,
+	"SIGBUS": true, "SIGFPE": true, "SIGKILL": true, "SIGUSR1": true, "SIGSEGV": true, "SIGUSR2": true,
+	"SIGPIPE": true, "SIGALRM": true, "SIGTERM": true, "SIGSTKFLT": true, "SIGCHLD": true, "SIGCONT": true,
+	"SIGSTOP": true, "SIGTSTP": true, "SIGTTIN": true, "SIGTTOU": true, "SIGURG": true, "SIGXCPU": true,
+	"SIGXFSZ": true, "SIGVTALRM": true, "SIGPROF": true, "SIGWINCH": true, "SIGIO": true, "SIGPWR": true,
+	"SIGSYS": true,
+}
+
+// realtimeSignalPattern matches real-time signal names such as SIGRTMIN+3
+var realtimeSignalPattern = regexp.MustCompile(`^SIGRTM(IN|AX)([+-][0-9]+)?$`)
+
+// normalizeSignal validates a signal given by name, with or without the SIG
+// prefix, or by number, and returns it in the form sent to the daemon
+func normalizeSignal(signal string) (string, error) {
+	if signal == "" {
+		return "SIGKILL", nil
+	}
+	if n, err := strconv.Atoi(signal); err == nil {
+		if n < 1 || n > 64 {
+			return "", fmt.Errorf("invalid signal %q: signal numbers range from 1 to 64", signal)
+		}
+		return signal, nil
+	}
+
+	name := strings.ToUpper(signal)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if !signalNames[name] && !realtimeSignalPattern.MatchString(name) {
+		return "", fmt.Errorf("invalid signal %q", signal)
+	}
+	return name, nil
+}
+
+// HandlePauseContainer handles container pause requests
+func (h *Handler) HandlePauseContainer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.simpleContainerAction(ctx, request, "pause", h.dockerClient.PauseContainer)
+}
+
+// HandleUnpauseContainer handles container unpause requests
+func (h *Handler) HandleUnpauseContainer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return h.simpleContainerAction(ctx, request, "unpause", h.dockerClient.UnpauseContainer)
+}
+
+// simpleContainerAction runs an action that takes no arguments besides the container
+func (h *Handler) simpleContainerAction(ctx context.Context, request mcp.CallToolRequest, action string, run func(ctx context.Context, containerID string) error) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	if h.dryRun(params) {
+		plan, err := h.planContainerAction(ctx, containerID, action, params)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
+
+	if err := run(ctx, containerID); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to %s container: %w", action, err))
+	}
+
+	return h.formatResponse(models.ContainerActionResponse{
+		ID:     containerID,
+		Action: action,
+		Status: "success",
+	})
+}
+
+// HandleKillContainer handles requests to send a signal to a container
+func (h *Handler) HandleKillContainer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	signalVal, _ := params["signal"].(string)
+	signal, err := normalizeSignal(signalVal)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	if h.dryRun(params) {
+		plan, err := h.planContainerAction(ctx, containerID, "kill", params)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
+
+	if err := h.dockerClient.KillContainer(ctx, containerID, signal); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to kill container: %w", err))
+	}
+
+	return h.formatResponse(models.ContainerActionResponse{
+		ID:     containerID,
+		Action: "kill",
+		Status: "success",
+	})
+}
+
+// HandleRenameContainer handles container rename requests
+func (h *Handler) HandleRenameContainer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	newName, ok := params["new_name"].(string)
+	if !ok || newName == "" {
+		return h.formatErrorResponse(fmt.Errorf("new_name is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	if h.dryRun(params) {
+		plan, err := h.planContainerAction(ctx, containerID, "rename", params)
+		if err != nil {
+			return h.formatErrorResponse(err)
+		}
+		return h.formatResponse(plan)
+	}
+
+	if err := h.dockerClient.RenameContainer(ctx, containerID, newName); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to rename container: %w", err))
+	}
+
+	return h.formatResponse(models.ContainerActionResponse{
+		ID:     containerID,
+		Action: "rename",
+		Status: "success",
+	})
+}
+
+// HandleWaitContainer handles requests to wait for a container to stop or be removed
+func (h *Handler) HandleWaitContainer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	condition := container.WaitConditionNotRunning
+	if conditionVal, ok := params["condition"].(string); ok && conditionVal != "" {
+		condition = container.WaitCondition(conditionVal)
+	}
+	switch condition {
+	case container.WaitConditionNotRunning, container.WaitConditionNextExit, container.WaitConditionRemoved:
+	default:
+		return h.formatErrorResponse(fmt.Errorf("invalid condition %q: must be not-running, next-exit or removed", condition))
+	}
+
+	timeout := defaultWaitTimeout
+	if timeoutVal, ok := params["timeout"].(float64); ok {
+		timeout = time.Duration(timeoutVal * float64(time.Second))
+		if timeout <= 0 || timeout > maxWaitTimeout {
+			return h.formatErrorResponse(fmt.Errorf("timeout must be greater than 0 and at most %s", maxWaitTimeout))
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response := &models.ContainerWaitResponse{
+		ContainerID: containerID,
+		Condition:   string(condition),
+	}
+	result, err := h.dockerClient.WaitContainer(waitCtx, containerID, condition)
+	switch {
+	case err != nil && ctx.Err() == nil && errors.Is(waitCtx.Err(), context.DeadlineExceeded):
+		response.ExitCode = -1
+		response.TimedOut = true
+	case err != nil:
+		return h.formatErrorResponse(fmt.Errorf("failed to wait for container: %w", err))
+	default:
+		response.ExitCode = result.StatusCode
+		if result.Error != nil {
+			response.Error = result.Error.Message
+		}
+	}
+
+	return h.formatResponse(response)
+}
@@ -0,0 +1,473 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coolbit-in/docker-mcp/pkg/docker"
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/coolbit-in/docker-mcp/pkg/notify"
+	"github.com/coolbit-in/docker-mcp/pkg/redact"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Log output formats
+const (
+	logFormatSplit  = "split"  // Separate stdout and stderr strings
+	logFormatTagged = "tagged" // One list of lines tagged with their stream, in output order
+)
+
+// defaultLogMaxBytes caps the returned log text unless the caller overrides it
+const defaultLogMaxBytes = 64 * 1024
+
+// Follow mode bounds
+const (
+	defaultFollowDuration = 30 * time.Second
+	maxFollowDuration     = 10 * time.Minute
+)
+
+// Reasons a follow session stopped
+const (
+	followStopDuration  = "duration"
+	followStopLineLimit = "line_limit"
+	followStopEnded     = "ended"
+	followStopCancelled = "cancelled"
+)
+
+// HandleContainerLogs handles container logs requests
+func (h *Handler) HandleContainerLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       "all",
+	}
+	if followVal, ok := params["follow"].(bool); ok {
+		options.Follow = followVal
+	}
+	if tsVal, ok := params["timestamps"].(bool); ok {
+		options.Timestamps = tsVal
+	}
+	if since, ok := params["since"].(string); ok {
+		options.Since = since
+	}
+	if until, ok := params["until"].(string); ok {
+		options.Until = until
+	}
+
+	tail, err := logTail(params["tail"])
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+	// Following streams new lines; replaying history needs an explicit tail
+	if _, ok := params["tail"]; !ok && options.Follow {
+		tail = "0"
+	}
+	options.Tail = tail
+
+	format := logFormatSplit
+	if formatVal, ok := params["format"].(string); ok && formatVal != "" {
+		format = formatVal
+	}
+	if format != logFormatSplit && format != logFormatTagged {
+		return h.formatErrorResponse(fmt.Errorf("invalid format %q: must be %q or %q", format, logFormatSplit, logFormatTagged))
+	}
+
+	filter, err := newLogFilter(params)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	maxLines := 0
+	if maxVal, ok := params["max_lines"].(float64); ok {
+		maxLines = int(maxVal)
+	}
+	maxBytes := defaultLogMaxBytes
+	if maxVal, ok := params["max_bytes"].(float64); ok {
+		maxBytes = int(maxVal)
+	}
+
+	// TTY containers write a raw stream without multiplexing headers
+	containerInfo, err := h.dockerClient.InspectContainer(ctx, containerID)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to get container logs: %w", err))
+	}
+	tty := containerInfo.Config != nil && containerInfo.Config.Tty
+
+	if options.Follow {
+		duration := defaultFollowDuration
+		if durationVal, ok := params["follow_duration"].(float64); ok && durationVal > 0 {
+			duration = time.Duration(durationVal * float64(time.Second))
+		}
+		if duration > maxFollowDuration {
+			return h.formatErrorResponse(fmt.Errorf("follow_duration must not exceed %s", maxFollowDuration))
+		}
+		lineLimit := 0
+		if limitVal, ok := params["follow_lines"].(float64); ok && limitVal > 0 {
+			lineLimit = int(limitVal)
+		}
+
+		session := &logFollower{
+			containerID:  containerID,
+			token:        notify.ProgressToken(request),
+			filter:       filter,
+			duration:     duration,
+			lineLimit:    lineLimit,
+			redactor:     h.redactor(params),
+			window:       &logWindow{maxLines: maxLines, maxBytes: maxBytes},
+			maxLineBytes: maxBytes,
+		}
+		if err := session.run(ctx, h.dockerClient, options, tty); err != nil {
+			return h.formatErrorResponse(fmt.Errorf("failed to follow logs: %w", err))
+		}
+
+		response := logsResponse(containerID, session.window.lines, session.window.omitted, format)
+		response.Follow = &session.summary
+		return h.formatResponse(response)
+	}
+
+	reader, err := h.dockerClient.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to get container logs: %w", err))
+	}
+	defer reader.Close()
+
+	// Only the lines that fit the limits are kept while reading, so a large
+	// log is never held in memory as a whole
+	window := &logWindow{maxLines: maxLines, maxBytes: maxBytes}
+	collector := &logCollector{redactor: h.redactor(params), maxLineBytes: maxBytes, onLine: func(line models.LogLine) {
+		if filter.match(line) {
+			window.add(line)
+		}
+	}}
+	if err := collector.readFrom(reader, tty); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to read logs: %w", err))
+	}
+
+	return h.formatResponse(logsResponse(containerID, window.lines, window.omitted, format))
+}
+
+// logFollower streams new log lines to the client as notifications
+type logFollower struct {
+	containerID string
+	token       mcp.ProgressToken
+	filter      *logFilter
+	duration    time.Duration
+	lineLimit   int
+	redactor    *redact.Redactor
+
+	window       *logWindow // Most recent streamed lines, returned in the response
+	maxLineBytes int        // Longest line kept whole (zero or less means no limit)
+	streamed     int
+	summary      models.LogsFollowSummary
+}
+
+// run follows the log stream until the duration or line limit is reached,
+// the stream ends (e.g. the container stopped), or ctx is cancelled
+func (f *logFollower) run(ctx context.Context, client docker.API, options container.LogsOptions, tty bool) error {
+	started := time.Now()
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reader, err := client.ContainerLogs(streamCtx, f.containerID, options)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	limitReached := make(chan struct{})
+	collector := &logCollector{redactor: f.redactor, maxLineBytes: f.maxLineBytes, onLine: func(line models.LogLine) {
+		if !f.filter.match(line) || (f.lineLimit > 0 && f.streamed >= f.lineLimit) {
+			return
+		}
+		f.streamed++
+		f.window.add(line)
+
+		// Delivery is best effort; clients that ignore notifications still get the summary
+		notify.Log(ctx, mcp.LoggingLevelInfo, "logs", map[string]interface{}{
+			"container_id": f.containerID,
+			"stream":       line.Stream,
+			"text":         line.Text,
+		})
+		notify.Progress(ctx, f.token, float64(f.streamed), float64(f.lineLimit), line.Text)
+
+		if f.lineLimit > 0 && f.streamed == f.lineLimit {
+			close(limitReached)
+		}
+	}}
+
+	readDone := make(chan error, 1)
+	go func() {
+		readDone <- collector.readFrom(reader, tty)
+	}()
+
+	timer := time.NewTimer(f.duration)
+	defer timer.Stop()
+
+	select {
+	case err := <-readDone:
+		if err != nil {
+			return fmt.Errorf("failed to read logs: %w", err)
+		}
+		f.summary.StopReason = followStopEnded
+	case <-limitReached:
+		f.summary.StopReason = followStopLineLimit
+	case <-timer.C:
+		f.summary.StopReason = followStopDuration
+	case <-ctx.Done():
+		f.summary.StopReason = followStopCancelled
+	}
+
+	// Closing the stream ends the reader; wait so the collected lines are safe to use
+	if f.summary.StopReason != followStopEnded {
+		reader.Close()
+		<-readDone
+	}
+
+	f.summary.StreamedLines = f.streamed
+	f.summary.DurationSeconds = time.Since(started).Seconds()
+	return nil
+}
+
+// logTail validates the tail parameter, accepting "all" or a non-negative line count
+func logTail(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "all", nil
+	case float64:
+		// Older clients send a number; negative values meant "all"
+		if v < 0 {
+			return "all", nil
+		}
+		return strconv.Itoa(int(v)), nil
+	case string:
+		if v == "" || v == "all" {
+			return "all", nil
+		}
+		if n, err := strconv.Atoi(v); err != nil || n < 0 {
+			return "", fmt.Errorf("invalid tail %q: must be \"all\" or a non-negative number", v)
+		}
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid tail: must be \"all\" or a non-negative number")
+	}
+}
+
+// logCollector splits demultiplexed output into lines, preserving their order across streams
+type logCollector struct {
+	pending map[string]*pendingLine
+
+	// onLine receives each complete line
+	onLine func(models.LogLine)
+
+	// redactor masks secrets in each line before it is filtered or recorded
+	redactor *redact.Redactor
+
+	// maxLineBytes caps the text kept of a single line; the rest is counted
+	// and replaced with a marker (zero or less means no limit)
+	maxLineBytes int
+}
+
+// pendingLine is the unterminated line of one stream
+type pendingLine struct {
+	buf     bytes.Buffer
+	dropped int // Bytes of the line beyond maxLineBytes
+}
+
+// readFrom reads a log stream until EOF. Raw (TTY) streams are attributed to stdout.
+func (c *logCollector) readFrom(r io.Reader, tty bool) error {
+	var err error
+	if tty {
+		_, err = io.Copy(c.writer("stdout"), r)
+	} else {
+		_, err = stdcopy.StdCopy(c.writer("stdout"), c.writer("stderr"), r)
+	}
+	c.flush()
+	return err
+}
+
+// writer returns an io.Writer that appends lines for the given stream
+func (c *logCollector) writer(stream string) io.Writer {
+	return logStreamWriter{collector: c, stream: stream}
+}
+
+// write buffers p and emits every complete line
+func (c *logCollector) write(stream string, p []byte) {
+	if c.pending == nil {
+		c.pending = make(map[string]*pendingLine)
+	}
+	pending, ok := c.pending[stream]
+	if !ok {
+		pending = &pendingLine{}
+		c.pending[stream] = pending
+	}
+
+	for len(p) > 0 {
+		chunk := p
+		i := bytes.IndexByte(p, '\n')
+		if i >= 0 {
+			chunk, p = p[:i], p[i+1:]
+		} else {
+			p = nil
+		}
+
+		keep := len(chunk)
+		if c.maxLineBytes > 0 {
+			keep = max(0, min(keep, c.maxLineBytes-pending.buf.Len()))
+		}
+		pending.buf.Write(chunk[:keep])
+		pending.dropped += len(chunk) - keep
+
+		if i >= 0 {
+			c.emit(stream, pending)
+		}
+	}
+}
+
+// emit records the pending line of a stream, marking any truncation
+func (c *logCollector) emit(stream string, pending *pendingLine) {
+	text := strings.TrimRight(pending.buf.String(), "\r")
+	text = c.redactor.String(text)
+	if pending.dropped > 0 {
+		text += fmt.Sprintf(" [... %d bytes truncated]", pending.dropped)
+	}
+	pending.buf.Reset()
+	pending.dropped = 0
+
+	c.onLine(models.LogLine{Stream: stream, Text: text})
+}
+
+// flush emits unterminated trailing output as final lines
+func (c *logCollector) flush() {
+	for _, stream := range []string{"stdout", "stderr"} {
+		if pending, ok := c.pending[stream]; ok && (pending.buf.Len() > 0 || pending.dropped > 0) {
+			c.emit(stream, pending)
+		}
+	}
+}
+
+// logStreamWriter feeds one output stream into a logCollector
+type logStreamWriter struct {
+	collector *logCollector
+	stream    string
+}
+
+// Write implements io.Writer
+func (w logStreamWriter) Write(p []byte) (int, error) {
+	w.collector.write(w.stream, p)
+	return len(p), nil
+}
+
+// logFilter selects lines with include/exclude regular expressions
+type logFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+// newLogFilter compiles the include and exclude parameters
+func newLogFilter(params map[string]interface{}) (*logFilter, error) {
+	f := &logFilter{}
+	if pattern, ok := params["include"].(string); ok && pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern: %w", err)
+		}
+		f.include = re
+	}
+	if pattern, ok := params["exclude"].(string); ok && pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+		}
+		f.exclude = re
+	}
+	return f, nil
+}
+
+// match reports whether a line passes the filter
+func (f *logFilter) match(line models.LogLine) bool {
+	if f.include != nil && !f.include.MatchString(line.Text) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.MatchString(line.Text) {
+		return false
+	}
+	return true
+}
+
+// logWindow keeps the most recent lines within maxLines and maxBytes (zero or
+// less means no limit) as they are added, counting the earlier lines dropped.
+// The newest line is always kept, even when it alone exceeds maxBytes.
+type logWindow struct {
+	maxLines int
+	maxBytes int
+
+	lines   []models.LogLine
+	size    int
+	omitted int
+}
+
+// add appends a line and drops the oldest lines that no longer fit
+func (w *logWindow) add(line models.LogLine) {
+	w.lines = append(w.lines, line)
+	w.size += len(line.Text) + 1
+
+	for len(w.lines) > 1 && ((w.maxLines > 0 && len(w.lines) > w.maxLines) || (w.maxBytes > 0 && w.size > w.maxBytes)) {
+		w.size -= len(w.lines[0].Text) + 1
+		w.lines[0] = models.LogLine{}
+		w.lines = w.lines[1:]
+		w.omitted++
+	}
+}
+
+// logsResponse renders lines in the requested format, prefixing a truncation marker when lines were dropped
+func logsResponse(containerID string, lines []models.LogLine, omitted int, format string) models.LogsResponse {
+	response := models.LogsResponse{
+		ContainerID:  containerID,
+		LineCount:    len(lines),
+		Truncated:    omitted > 0,
+		OmittedLines: omitted,
+	}
+	marker := fmt.Sprintf("[... %d earlier lines truncated ...]", omitted)
+
+	if format == logFormatTagged {
+		response.Lines = make([]models.LogLine, 0, len(lines)+1)
+		if omitted > 0 {
+			response.Lines = append(response.Lines, models.LogLine{Stream: "truncated", Text: marker})
+		}
+		response.Lines = append(response.Lines, lines...)
+		return response
+	}
+
+	var stdout, stderr strings.Builder
+	if omitted > 0 {
+		stdout.WriteString(marker + "\n")
+	}
+	for _, line := range lines {
+		if line.Stream == "stderr" {
+			stderr.WriteString(line.Text + "\n")
+		} else {
+			stdout.WriteString(line.Text + "\n")
+		}
+	}
+	response.Stdout = stdout.String()
+	response.Stderr = stderr.String()
+	return response
+}
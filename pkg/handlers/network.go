@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// HandleListNetworks handles network listing requests
+// Supports optional 'name' and 'driver' filters
+func (h *Handler) HandleListNetworks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	filterArgs := filters.NewArgs()
+	if name, ok := params["name"].(string); ok && name != "" {
+		filterArgs.Add("name", name)
+	}
+	if driver, ok := params["driver"].(string); ok && driver != "" {
+		filterArgs.Add("driver", driver)
+	}
+
+	networks, err := h.dockerClient.ListNetworks(ctx, filterArgs)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to list networks: %w", err))
+	}
+
+	var result []models.NetworkInfo
+	for _, n := range networks {
+		networkInfo := models.NetworkInfo{
+			ID:         n.ID,
+			Name:       n.Name,
+			Driver:     n.Driver,
+			Scope:      n.Scope,
+			Internal:   n.Internal,
+			Attachable: n.Attachable,
+			EnableIPv6: n.EnableIPv6,
+			Labels:     n.Labels,
+			Created:    n.Created,
+		}
+
+		for _, cfg := range n.IPAM.Config {
+			if cfg.Subnet != "" {
+				networkInfo.Subnets = append(networkInfo.Subnets, cfg.Subnet)
+			}
+		}
+
+		result = append(result, networkInfo)
+	}
+
+	return h.formatResponse(result)
+}
+
+// HandleInspectNetwork handles network inspection requests
+func (h *Handler) HandleInspectNetwork(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	networkID, ok := params["network_id"].(string)
+	if !ok || networkID == "" {
+		return h.formatErrorResponse(fmt.Errorf("network_id is required"))
+	}
+
+	networkInfo, err := h.dockerClient.InspectNetwork(ctx, networkID)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to inspect network: %w", err))
+	}
+
+	// Containers outside the caller's scope are left out, as in list_containers
+	for containerID := range networkInfo.Containers {
+		if err := h.checkScope(ctx, containerID); err != nil {
+			delete(networkInfo.Containers, containerID)
+		}
+	}
+
+	// Convert to JSON
+	details, err := json.Marshal(networkInfo)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to serialize network information: %w", err))
+	}
+
+	return h.formatResponse(models.InspectResponse{
+		ID:      networkID,
+		Type:    "network",
+		Details: details,
+	})
+}
+
+// HandleCreateNetwork handles network creation requests
+func (h *Handler) HandleCreateNetwork(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	name, ok := params["name"].(string)
+	if !ok || name == "" {
+		return h.formatErrorResponse(fmt.Errorf("name is required"))
+	}
+
+	options := network.CreateOptions{
+		Driver:  "bridge",
+		Options: stringMap(params, "options"),
+		Labels:  stringMap(params, "labels"),
+	}
+
+	if driver, ok := params["driver"].(string); ok && driver != "" {
+		options.Driver = driver
+	}
+	if internal, ok := params["internal"].(bool); ok {
+		options.Internal = internal
+	}
+	if attachable, ok := params["attachable"].(bool); ok {
+		options.Attachable = attachable
+	}
+	if enableIPv6, ok := params["enable_ipv6"].(bool); ok {
+		options.EnableIPv6 = &enableIPv6
+	}
+
+	// Optional IPAM configuration
+	ipamConfig := network.IPAMConfig{}
+	if subnet, ok := params["subnet"].(string); ok {
+		ipamConfig.Subnet = subnet
+	}
+	if gateway, ok := params["gateway"].(string); ok {
+		ipamConfig.Gateway = gateway
+	}
+	if ipRange, ok := params["ip_range"].(string); ok {
+		ipamConfig.IPRange = ipRange
+	}
+	if ipamConfig.Subnet != "" || ipamConfig.Gateway != "" || ipamConfig.IPRange != "" {
+		if ipamConfig.Subnet == "" {
+			return h.formatErrorResponse(fmt.Errorf("subnet is required when gateway or ip_range is set"))
+		}
+		options.IPAM = &network.IPAM{
+			Driver: "default",
+			Config: []network.IPAMConfig{ipamConfig},
+		}
+	}
+
+	resp, err := h.dockerClient.CreateNetwork(ctx, name, options)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to create network: %w", err))
+	}
+
+	return h.formatResponse(models.NetworkCreatedResponse{
+		ID:      resp.ID,
+		Name:    name,
+		Warning: resp.Warning,
+	})
+}
+
+// HandleRemoveNetwork handles network removal requests
+func (h *Handler) HandleRemoveNetwork(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	networkID, ok := params["network_id"].(string)
+	if !ok || networkID == "" {
+		return h.formatErrorResponse(fmt.Errorf("network_id is required"))
+	}
+
+	if err := h.dockerClient.RemoveNetwork(ctx, networkID); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to remove network: %w", err))
+	}
+
+	return h.formatResponse(models.NetworkActionResponse{
+		NetworkID: networkID,
+		Action:    "remove",
+		Status:    "success",
+	})
+}
+
+// HandlePruneNetworks handles requests to remove unused networks
+func (h *Handler) HandlePruneNetworks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	filterArgs := filters.NewArgs()
+	if until, ok := params["until"].(string); ok && until != "" {
+		filterArgs.Add("until", until)
+	}
+	for _, label := range stringSlice(params, "labels") {
+		filterArgs.Add("label", label)
+	}
+
+	report, err := h.dockerClient.PruneNetworks(ctx, filterArgs)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to prune networks: %w", err))
+	}
+
+	deleted := report.NetworksDeleted
+	if deleted == nil {
+		deleted = []string{}
+	}
+
+	return h.formatResponse(models.PruneResponse{
+		Deleted: deleted,
+	})
+}
+
+// HandleConnectNetwork handles requests to connect a container to a network
+func (h *Handler) HandleConnectNetwork(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	networkID, ok := params["network_id"].(string)
+	if !ok || networkID == "" {
+		return h.formatErrorResponse(fmt.Errorf("network_id is required"))
+	}
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	if err := h.dockerClient.ConnectNetwork(ctx, networkID, containerID, endpointSettings(params)); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to connect container to network: %w", err))
+	}
+
+	return h.formatResponse(models.NetworkActionResponse{
+		NetworkID:   networkID,
+		ContainerID: containerID,
+		Action:      "connect",
+		Status:      "success",
+	})
+}
+
+// HandleDisconnectNetwork handles requests to disconnect a container from a network
+func (h *Handler) HandleDisconnectNetwork(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	networkID, ok := params["network_id"].(string)
+	if !ok || networkID == "" {
+		return h.formatErrorResponse(fmt.Errorf("network_id is required"))
+	}
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	force := false
+	if forceVal, ok := params["force"].(bool); ok {
+		force = forceVal
+	}
+
+	if err := h.dockerClient.DisconnectNetwork(ctx, networkID, containerID, force); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to disconnect container from network: %w", err))
+	}
+
+	return h.formatResponse(models.NetworkActionResponse{
+		NetworkID:   networkID,
+		ContainerID: containerID,
+		Action:      "disconnect",
+		Status:      "success",
+	})
+}
+
+// endpointSettings builds endpoint settings from aliases, static addresses and driver options
+func endpointSettings(params map[string]interface{}) *network.EndpointSettings {
+	settings := &network.EndpointSettings{
+		Aliases:    stringSlice(params, "aliases"),
+		DriverOpts: stringMap(params, "driver_opts"),
+	}
+
+	ipv4, _ := params["ipv4_address"].(string)
+	ipv6, _ := params["ipv6_address"].(string)
+	if ipv4 != "" || ipv6 != "" {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{
+			IPv4Address: ipv4,
+			IPv6Address: ipv6,
+		}
+	}
+
+	return settings
+}
+
+// networkingConfig builds the endpoint configuration for the 'networks' parameter of create_container.
+// Each element is either a network name or an object with 'name' and optional endpoint settings.
+func networkingConfig(params map[string]interface{}) (*network.NetworkingConfig, []string, error) {
+	networks, ok := params["networks"].([]interface{})
+	if !ok || len(networks) == 0 {
+		return nil, nil, nil
+	}
+
+	config := &network.NetworkingConfig{
+		EndpointsConfig: make(map[string]*network.EndpointSettings, len(networks)),
+	}
+	var names []string
+
+	for _, n := range networks {
+		switch v := n.(type) {
+		case string:
+			if v == "" {
+				return nil, nil, fmt.Errorf("network name must not be empty")
+			}
+			config.EndpointsConfig[v] = &network.EndpointSettings{}
+			names = append(names, v)
+		case map[string]interface{}:
+			name, _ := v["name"].(string)
+			if name == "" {
+				return nil, nil, fmt.Errorf("network name is required")
+			}
+			config.EndpointsConfig[name] = endpointSettings(v)
+			names = append(names, name)
+		default:
+			return nil, nil, fmt.Errorf("invalid network specification: %v", n)
+		}
+	}
+
+	return config, names, nil
+}
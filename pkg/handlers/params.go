@@ -0,0 +1,39 @@
+package handlers
+
+import "fmt"
+
+// stringSlice extracts an array of strings from the request parameters.
+// Non-string elements are ignored.
+func stringSlice(params map[string]interface{}, key string) []string {
+	array, ok := params[key].([]interface{})
+	if !ok || len(array) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(array))
+	for _, v := range array {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// stringMap extracts an object of string values from the request parameters.
+// Non-string values are converted with their default formatting.
+func stringMap(params map[string]interface{}, key string) map[string]string {
+	obj, ok := params[key].(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		} else {
+			result[k] = fmt.Sprint(v)
+		}
+	}
+	return result
+}
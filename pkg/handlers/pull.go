@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/coolbit-in/docker-mcp/pkg/notify"
+	"github.com/docker/go-units"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// HandlePullImage handles image pull requests, reporting layer progress as MCP progress notifications
+func (h *Handler) HandlePullImage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+	imageName, ok := params["image_name"].(string)
+	if !ok || imageName == "" {
+		return h.formatErrorResponse(fmt.Errorf("image_name is required"))
+	}
+
+	// Call Docker API to pull image
+	reader, err := h.dockerClient.PullImage(ctx, imageName)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to pull image: %w", err))
+	}
+	defer reader.Close()
+
+	tracker := newPullTracker()
+	token := notify.ProgressToken(request)
+
+	// Handle streaming response
+	decoder := json.NewDecoder(reader)
+	for {
+		var event models.ProgressEvent
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return h.formatErrorResponse(fmt.Errorf("failed to decode progress event: %w", err))
+		}
+
+		// Errors are reported in-band; the HTTP status is already 200
+		if msg := progressError(event); msg != "" {
+			return h.formatErrorResponse(fmt.Errorf("failed to pull image: %s", msg))
+		}
+
+		if tracker.update(event) {
+			notify.Progress(ctx, token, tracker.percent, 100, tracker.message())
+		}
+	}
+
+	if ctx.Err() != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to pull image: %w", ctx.Err()))
+	}
+	tracker.percent = 100
+	notify.Progress(ctx, token, tracker.percent, 100, tracker.message())
+
+	result := models.PullProgressResponse{
+		ImageName: imageName,
+		Status:    "success",
+		Complete:  true,
+		Digest:    tracker.digest,
+		Message:   tracker.final,
+		Layers:    len(tracker.layers),
+	}
+
+	return h.formatResponse(result)
+}
+
+// progressError returns the error carried by a stream event, if any
+func progressError(event models.ProgressEvent) string {
+	if event.ErrorDetail != nil && event.ErrorDetail.Message != "" {
+		return event.ErrorDetail.Message
+	}
+	return event.Error
+}
+
+// layerProgress tracks the download and extraction of one image layer
+type layerProgress struct {
+	total      int64 // Layer size, 0 until the daemon reports it
+	downloaded int64
+	extracted  int64
+	done       bool
+}
+
+// pullTracker aggregates per-layer progress events into an overall percentage.
+// Each layer counts its bytes twice, once downloaded and once extracted, so the
+// percentage keeps moving during extraction. Layers already present locally
+// are complete but add no bytes.
+type pullTracker struct {
+	layers  map[string]*layerProgress
+	percent float64 // Last reported percentage; never decreases
+	digest  string
+	final   string
+}
+
+// newPullTracker creates an empty tracker
+func newPullTracker() *pullTracker {
+	return &pullTracker{layers: make(map[string]*layerProgress)}
+}
+
+// update applies an event and reports whether the overall percentage increased
+func (t *pullTracker) update(event models.ProgressEvent) bool {
+	switch {
+	case strings.HasPrefix(event.Status, "Digest: "):
+		t.digest = strings.TrimPrefix(event.Status, "Digest: ")
+		return false
+	case strings.HasPrefix(event.Status, "Status: "):
+		t.final = strings.TrimPrefix(event.Status, "Status: ")
+		return false
+	case event.ID == "" || strings.HasPrefix(event.Status, "Pulling from "):
+		return false
+	}
+
+	layer, ok := t.layers[event.ID]
+	if !ok {
+		layer = &layerProgress{}
+		t.layers[event.ID] = layer
+	}
+
+	detail := event.ProgressDetail
+	if detail.Total > 0 {
+		layer.total = detail.Total
+	}
+	switch event.Status {
+	case "Downloading":
+		layer.downloaded = detail.Current
+	case "Download complete", "Verifying Checksum":
+		layer.downloaded = layer.total
+	case "Extracting":
+		layer.downloaded = layer.total
+		layer.extracted = detail.Current
+	case "Pull complete", "Already exists":
+		layer.downloaded, layer.extracted = layer.total, layer.total
+		layer.done = true
+	}
+
+	percent := t.overall()
+	if percent <= t.percent {
+		return false
+	}
+	t.percent = percent
+	return true
+}
+
+// overall computes the aggregate completion percentage. Layers keep being
+// announced while the pull runs, so 100 is only reported once the stream ends.
+func (t *pullTracker) overall() float64 {
+	var current, total int64
+	for _, layer := range t.layers {
+		current += layer.downloaded + layer.extracted
+		total += 2 * layer.total
+	}
+	if total == 0 {
+		return 0
+	}
+	// Whole percentages keep the notification rate bounded
+	return min(math.Floor(float64(current)*100/float64(total)), 99)
+}
+
+// message summarises the pull state for a progress notification
+func (t *pullTracker) message() string {
+	var done int
+	var downloaded, total int64
+	for _, layer := range t.layers {
+		if layer.done {
+			done++
+		}
+		downloaded += layer.downloaded
+		total += layer.total
+	}
+	return fmt.Sprintf("%d/%d layers complete, %s/%s downloaded", done, len(t.layers), units.HumanSize(float64(downloaded)), units.HumanSize(float64(total)))
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import "github.com/coolbit-in/docker-mcp/pkg/redact"
+
+// redactor returns the redactor for a call's output, or nil when the call
+// opts out with redact set to false
+func (h *Handler) redactor(params map[string]interface{}) *redact.Redactor {
+	if enabled, ok := params["redact"].(bool); ok && !enabled {
+		return nil
+	}
+	return h.options.Redactor
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// principalPlaceholder in the scope label value is replaced with the caller's principal name
+const principalPlaceholder = "{principal}"
+
+// scopeLabel returns the label that create_container stamps on new containers
+// and that every other container tool requires. ok is false when scoping is disabled.
+func (h *Handler) scopeLabel(ctx context.Context) (key, value string, ok bool) {
+	if h.options.ScopeLabel == "" {
+		return "", "", false
+	}
+
+	key, value, _ = strings.Cut(h.options.ScopeLabel, "=")
+	if strings.Contains(value, principalPlaceholder) {
+		principal, found := auth.FromContext(ctx)
+		if !found {
+			principal = auth.Anonymous
+		}
+		value = strings.ReplaceAll(value, principalPlaceholder, principal.Name)
+	}
+	return key, value, true
+}
+
+// inScope reports whether a container with the given labels may be managed by the caller
+func (h *Handler) inScope(ctx context.Context, labels map[string]string) bool {
+	key, value, ok := h.scopeLabel(ctx)
+	if !ok {
+		return true
+	}
+	got, found := labels[key]
+	return found && got == value
+}
+
+// checkScope fails unless the container carries the caller's scope label
+func (h *Handler) checkScope(ctx context.Context, containerID string) error {
+	if _, _, ok := h.scopeLabel(ctx); !ok {
+		return nil
+	}
+	_, err := h.inspectInScope(ctx, containerID)
+	return err
+}
+
+// inspectInScope inspects a container and fails unless it carries the
+// caller's scope label, for callers that need the details anyway
+func (h *Handler) inspectInScope(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	info, err := h.dockerClient.InspectContainer(ctx, containerID)
+	if err != nil {
+		return types.ContainerJSON{}, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if key, value, ok := h.scopeLabel(ctx); ok && (info.Config == nil || !h.inScope(ctx, info.Config.Labels)) {
+		return types.ContainerJSON{}, fmt.Errorf("container %s is outside this server's scope: it lacks the label %s=%s", containerID, key, value)
+	}
+	return info, nil
+}
+
+// checkReferencedScope fails unless the container whose network namespace a
+// new container would join through network_mode container:<id> is in the caller's scope
+func (h *Handler) checkReferencedScope(ctx context.Context, hostConfig *container.HostConfig) error {
+	if !hostConfig.NetworkMode.IsContainer() {
+		return nil
+	}
+	return h.checkScope(ctx, hostConfig.NetworkMode.ConnectedContainer())
+}
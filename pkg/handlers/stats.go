@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/docker/docker/api/types/container"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Stats sampling window bounds
+const (
+	defaultStatsWindow = time.Second
+	maxStatsWindow     = 10 * time.Second
+)
+
+// maxStatsConcurrency is the number of containers sampled at the same time
+const maxStatsConcurrency = 8
+
+// HandleContainerStats handles container resource usage requests. Each
+// container is sampled twice, a window apart, so that CPU usage and I/O
+// rates reflect current activity rather than lifetime averages. At most
+// maxStatsConcurrency containers are sampled at once.
+func (h *Handler) HandleContainerStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerIDs := stringSlice(params, "container_ids")
+	if containerID, ok := params["container_id"].(string); ok && containerID != "" {
+		containerIDs = append(containerIDs, containerID)
+	}
+	containerIDs = dedupe(containerIDs)
+
+	window := defaultStatsWindow
+	if windowVal, ok := params["window"].(float64); ok {
+		window = time.Duration(windowVal * float64(time.Second))
+		if window <= 0 || window > maxStatsWindow {
+			return h.formatErrorResponse(fmt.Errorf("window must be greater than 0 and at most %s", maxStatsWindow))
+		}
+	}
+
+	// Without explicit containers, sample every running container in scope
+	if len(containerIDs) == 0 {
+		containers, err := h.dockerClient.ListContainers(ctx, false)
+		if err != nil {
+			return h.formatErrorResponse(fmt.Errorf("failed to list containers: %w", err))
+		}
+		for _, c := range containers {
+			if h.inScope(ctx, c.Labels) {
+				containerIDs = append(containerIDs, c.ID)
+			}
+		}
+	}
+
+	response := &models.ContainerStatsResponse{
+		SampleSeconds: window.Seconds(),
+		Containers:    make([]models.ContainerStats, len(containerIDs)),
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxStatsConcurrency)
+	for i, containerID := range containerIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			response.Containers[i] = h.sampleContainerStats(ctx, containerID, window)
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("stats sampling cancelled: %w", err))
+	}
+	return h.formatResponse(response)
+}
+
+// sampleContainerStats measures one container's usage over the window.
+// Failures are reported in the entry so the other containers are still returned.
+func (h *Handler) sampleContainerStats(ctx context.Context, containerID string, window time.Duration) models.ContainerStats {
+	result := models.ContainerStats{ID: containerID}
+
+	info, err := h.inspectInScope(ctx, containerID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ID = info.ID
+	result.Name = strings.TrimPrefix(info.Name, "/")
+	if info.State == nil || !info.State.Running {
+		result.Error = "container is not running"
+		return result
+	}
+
+	first, err := h.dockerClient.ContainerStats(ctx, info.ID)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to get container stats: %v", err)
+		return result
+	}
+	start := time.Now()
+
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		result.Error = ctx.Err().Error()
+		return result
+	case <-timer.C:
+	}
+
+	second, err := h.dockerClient.ContainerStats(ctx, info.ID)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to get container stats: %v", err)
+		return result
+	}
+
+	// Prefer the daemon's read timestamps, which exclude request latency
+	elapsed := time.Since(start)
+	if !first.Read.IsZero() && second.Read.After(first.Read) {
+		elapsed = second.Read.Sub(first.Read)
+	}
+
+	summarizeStats(&result, first, second, elapsed)
+	return result
+}
+
+// summarizeStats fills in usage totals from the second sample and CPU usage
+// and rates from the difference between the two
+func summarizeStats(result *models.ContainerStats, first, second container.StatsResponse, elapsed time.Duration) {
+	// CPU usage relative to the host's, scaled so one full CPU is 100%
+	cpuDelta := float64(second.CPUStats.CPUUsage.TotalUsage) - float64(first.CPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(second.CPUStats.SystemUsage) - float64(first.CPUStats.SystemUsage)
+	onlineCPUs := float64(second.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(second.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		result.CPUPercent = round2(cpuDelta / systemDelta * onlineCPUs * 100)
+	}
+
+	result.MemoryUsage = memoryUsage(second.MemoryStats)
+	result.MemoryLimit = second.MemoryStats.Limit
+	if result.MemoryLimit > 0 {
+		result.MemoryPercent = round2(float64(result.MemoryUsage) / float64(result.MemoryLimit) * 100)
+	}
+
+	firstRx, firstTx := networkTotals(first)
+	result.NetworkRxBytes, result.NetworkTxBytes = networkTotals(second)
+	firstRead, firstWrite := blockTotals(first)
+	result.BlockReadBytes, result.BlockWriteBytes = blockTotals(second)
+
+	result.NetworkRxRate = rate(firstRx, result.NetworkRxBytes, elapsed)
+	result.NetworkTxRate = rate(firstTx, result.NetworkTxBytes, elapsed)
+	result.BlockReadRate = rate(firstRead, result.BlockReadBytes, elapsed)
+	result.BlockWriteRate = rate(firstWrite, result.BlockWriteBytes, elapsed)
+
+	result.PIDs = second.PidsStats.Current
+}
+
+// memoryUsage returns the memory in use without the inactive page cache, as
+// docker stats reports it (cgroup v1 and v2 name the counter differently)
+func memoryUsage(stats container.MemoryStats) uint64 {
+	for _, key := range []string{"total_inactive_file", "inactive_file"} {
+		if cache, ok := stats.Stats[key]; ok && cache < stats.Usage {
+			return stats.Usage - cache
+		}
+	}
+	return stats.Usage
+}
+
+// networkTotals sums the bytes received and sent on all interfaces
+func networkTotals(stats container.StatsResponse) (rx, tx uint64) {
+	for _, n := range stats.Networks {
+		rx += n.RxBytes
+		tx += n.TxBytes
+	}
+	return rx, tx
+}
+
+// blockTotals sums the bytes read from and written to all block devices
+func blockTotals(stats container.StatsResponse) (read, write uint64) {
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			read += entry.Value
+		case "write":
+			write += entry.Value
+		}
+	}
+	return read, write
+}
+
+// rate returns the per-second change of a counter, or 0 if it was reset
+func rate(before, after uint64, elapsed time.Duration) float64 {
+	if after < before || elapsed <= 0 {
+		return 0
+	}
+	return round2(float64(after-before) / elapsed.Seconds())
+}
+
+// dedupe returns the values without repeats, in their first order
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// round2 rounds to two decimal places
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultRestartRetries is the retry count of an on-failure restart policy that does not set one
+const defaultRestartRetries = 3
+
+// updateSetting is a container setting that update_container can change
+type updateSetting struct {
+	param string                                                        // Tool parameter
+	apply func(value interface{}, update *container.UpdateConfig) error // Parses the parameter into the update
+	show  func(hostConfig *container.HostConfig) string                 // Formats the current value
+}
+
+// updateSettings are the settings update_container can change, in the order they are reported
+var updateSettings = []updateSetting{
+	{
+		param: "cpu_shares",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			n, err := positiveInt("cpu_shares", v)
+			u.CPUShares = n
+			return err
+		},
+		show: func(hc *container.HostConfig) string { return intOr(hc.CPUShares, "default") },
+	},
+	{
+		param: "cpus",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			cpus, ok := v.(float64)
+			if !ok || cpus <= 0 {
+				return fmt.Errorf("cpus must be positive")
+			}
+			u.NanoCPUs = int64(cpus * 1e9)
+			return nil
+		},
+		show: func(hc *container.HostConfig) string {
+			if hc.NanoCPUs <= 0 {
+				return "unlimited"
+			}
+			return strconv.FormatFloat(float64(hc.NanoCPUs)/1e9, 'f', -1, 64)
+		},
+	},
+	{
+		param: "cpu_period",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			n, err := positiveInt("cpu_period", v)
+			u.CPUPeriod = n
+			return err
+		},
+		show: func(hc *container.HostConfig) string { return intOr(hc.CPUPeriod, "default") },
+	},
+	{
+		param: "cpu_quota",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			// -1 removes the quota
+			if n, ok := v.(float64); ok && n == -1 {
+				u.CPUQuota = -1
+				return nil
+			}
+			n, err := positiveInt("cpu_quota", v)
+			u.CPUQuota = n
+			return err
+		},
+		show: func(hc *container.HostConfig) string { return intOr(hc.CPUQuota, "unlimited") },
+	},
+	{
+		param: "cpuset_cpus",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			s, err := nonEmptyString("cpuset_cpus", v)
+			u.CpusetCpus = s
+			return err
+		},
+		show: func(hc *container.HostConfig) string { return stringOr(hc.CpusetCpus, "all") },
+	},
+	{
+		param: "cpuset_mems",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			s, err := nonEmptyString("cpuset_mems", v)
+			u.CpusetMems = s
+			return err
+		},
+		show: func(hc *container.HostConfig) string { return stringOr(hc.CpusetMems, "all") },
+	},
+	{
+		param: "memory",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			n, err := memorySize("memory", v)
+			u.Memory = n
+			return err
+		},
+		show: func(hc *container.HostConfig) string { return bytesOr(hc.Memory, "unlimited") },
+	},
+	{
+		param: "memory_reservation",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			n, err := memorySize("memory_reservation", v)
+			u.MemoryReservation = n
+			return err
+		},
+		show: func(hc *container.HostConfig) string { return bytesOr(hc.MemoryReservation, "none") },
+	},
+	{
+		param: "memory_swap",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			// -1, as a number or a string, allows unlimited swap
+			if n, ok := v.(float64); ok && n == -1 {
+				u.MemorySwap = -1
+				return nil
+			}
+			if s, ok := v.(string); ok && s == "-1" {
+				u.MemorySwap = -1
+				return nil
+			}
+			n, err := memorySize("memory_swap", v)
+			u.MemorySwap = n
+			return err
+		},
+		show: func(hc *container.HostConfig) string {
+			if hc.MemorySwap < 0 {
+				return "unlimited"
+			}
+			return bytesOr(hc.MemorySwap, "default")
+		},
+	},
+	{
+		param: "pids_limit",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			n, ok := v.(float64)
+			if !ok || n != float64(int64(n)) {
+				return fmt.Errorf("pids_limit must be a whole number")
+			}
+			// Zero or less removes the limit
+			pids := int64(n)
+			if pids <= 0 {
+				pids = -1
+			}
+			u.PidsLimit = &pids
+			return nil
+		},
+		show: func(hc *container.HostConfig) string {
+			if hc.PidsLimit == nil || *hc.PidsLimit <= 0 {
+				return "unlimited"
+			}
+			return strconv.FormatInt(*hc.PidsLimit, 10)
+		},
+	},
+	{
+		param: "blkio_weight",
+		apply: func(v interface{}, u *container.UpdateConfig) error {
+			n, ok := v.(float64)
+			if !ok || n < 10 || n > 1000 || n != float64(int64(n)) {
+				return fmt.Errorf("blkio_weight must be a whole number between 10 and 1000")
+			}
+			u.BlkioWeight = uint16(n)
+			return nil
+		},
+		show: func(hc *container.HostConfig) string { return intOr(int64(hc.BlkioWeight), "default") },
+	},
+}
+
+// restartPolicySetting reports the restart policy, which is parsed together
+// with its retry count by restartPolicy
+var restartPolicySetting = updateSetting{
+	param: "restart_policy",
+	show: func(hc *container.HostConfig) string {
+		restart := hc.RestartPolicy
+		if restart.Name == "" {
+			return "no"
+		}
+		if restart.IsOnFailure() {
+			return fmt.Sprintf("%s:%d", restart.Name, restart.MaximumRetryCount)
+		}
+		return string(restart.Name)
+	},
+}
+
+// restartPolicy parses the restart_policy and restart_max_retries parameters.
+// ok is false when no restart policy is given.
+func restartPolicy(params map[string]interface{}) (restart container.RestartPolicy, ok bool, err error) {
+	name, _ := params["restart_policy"].(string)
+	retriesVal, hasRetries := params["restart_max_retries"].(float64)
+
+	if name == "" {
+		if hasRetries {
+			return restart, false, fmt.Errorf("restart_max_retries requires restart_policy on-failure")
+		}
+		return restart, false, nil
+	}
+
+	restart.Name = container.RestartPolicyMode(name)
+	switch restart.Name {
+	case container.RestartPolicyDisabled, container.RestartPolicyAlways, container.RestartPolicyUnlessStopped:
+		if hasRetries {
+			return restart, false, fmt.Errorf("restart_max_retries requires restart_policy on-failure")
+		}
+	case container.RestartPolicyOnFailure:
+		restart.MaximumRetryCount = defaultRestartRetries
+		if hasRetries {
+			if retriesVal < 0 {
+				return restart, false, fmt.Errorf("restart_max_retries must not be negative")
+			}
+			restart.MaximumRetryCount = int(retriesVal)
+		}
+	default:
+		return restart, false, fmt.Errorf("invalid restart_policy %q: must be no, always, on-failure or unless-stopped", name)
+	}
+	return restart, true, nil
+}
+
+// HandleUpdateContainer handles requests to change a container's resource
+// limits and restart policy in place
+func (h *Handler) HandleUpdateContainer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	containerID, ok := params["container_id"].(string)
+	if !ok || containerID == "" {
+		return h.formatErrorResponse(fmt.Errorf("container_id is required"))
+	}
+	if err := h.checkScope(ctx, containerID); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	var update container.UpdateConfig
+	var requested []updateSetting
+	for _, setting := range updateSettings {
+		value, ok := params[setting.param]
+		if !ok || value == nil || value == "" {
+			continue
+		}
+		if err := setting.apply(value, &update); err != nil {
+			return h.formatErrorResponse(err)
+		}
+		requested = append(requested, setting)
+	}
+	restart, hasRestart, err := restartPolicy(params)
+	if err != nil {
+		return h.formatErrorResponse(err)
+	}
+	if hasRestart {
+		update.RestartPolicy = restart
+		requested = append(requested, restartPolicySetting)
+	}
+
+	if len(requested) == 0 {
+		return h.formatErrorResponse(fmt.Errorf("at least one setting to update is required"))
+	}
+	if err := checkResources(update.Resources); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	info, err := h.dockerClient.InspectContainer(ctx, containerID)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to inspect container: %w", err))
+	}
+	before := *info.HostConfig
+	planned := applyUpdate(before, update)
+	name := strings.TrimPrefix(info.Name, "/")
+
+	// Checks the daemon makes against the current settings
+	switch {
+	case update.Memory > 0 && update.MemorySwap == 0 && before.MemorySwap > 0 && update.Memory > before.MemorySwap:
+		return h.formatErrorResponse(fmt.Errorf("memory must not exceed the current memory_swap of %s; update memory_swap at the same time", units.BytesSize(float64(before.MemorySwap))))
+	case hasRestart && before.AutoRemove && restart.Name != container.RestartPolicyDisabled:
+		return h.formatErrorResponse(fmt.Errorf("cannot set restart policy %s: container %s has auto_remove set", restart.Name, name))
+	}
+
+	if err := h.options.Policy.CheckUpdate(before.Resources, planned.Resources); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	if h.dryRun(params) {
+		plan := &models.DryRunResponse{
+			DryRun: true,
+			Action: "update",
+			ID:     info.ID,
+			Name:   name,
+			State:  info.State.Status,
+		}
+		for _, setting := range requested {
+			from, to := setting.show(&before), setting.show(&planned)
+			if from == to {
+				plan.Plan = append(plan.Plan, fmt.Sprintf("keep %s at %s", setting.param, from))
+				continue
+			}
+			plan.Plan = append(plan.Plan, fmt.Sprintf("change %s from %s to %s", setting.param, from, to))
+		}
+		return h.formatResponse(plan)
+	}
+
+	result, err := h.dockerClient.UpdateContainer(ctx, containerID, update)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to update container: %w", err))
+	}
+
+	// Report the values the daemon applied rather than the ones requested
+	updated, err := h.dockerClient.InspectContainer(ctx, containerID)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to inspect updated container: %w", err))
+	}
+
+	response := &models.ContainerUpdateResponse{
+		ContainerID: containerID,
+		Changes:     make([]models.SettingChange, 0, len(requested)),
+		Warnings:    result.Warnings,
+	}
+	for _, setting := range requested {
+		response.Changes = append(response.Changes, models.SettingChange{
+			Setting: setting.param,
+			Before:  setting.show(&before),
+			After:   setting.show(updated.HostConfig),
+		})
+	}
+
+	return h.formatResponse(response)
+}
+
+// applyUpdate returns the host configuration that results from an update.
+// As in the daemon, zero values leave a setting unchanged.
+func applyUpdate(hostConfig container.HostConfig, update container.UpdateConfig) container.HostConfig {
+	r, u := &hostConfig.Resources, update.Resources
+	if u.CPUShares != 0 {
+		r.CPUShares = u.CPUShares
+	}
+	if u.NanoCPUs != 0 {
+		r.NanoCPUs = u.NanoCPUs
+	}
+	if u.CPUPeriod != 0 {
+		r.CPUPeriod = u.CPUPeriod
+	}
+	if u.CPUQuota != 0 {
+		r.CPUQuota = u.CPUQuota
+	}
+	if u.CpusetCpus != "" {
+		r.CpusetCpus = u.CpusetCpus
+	}
+	if u.CpusetMems != "" {
+		r.CpusetMems = u.CpusetMems
+	}
+	if u.Memory != 0 {
+		r.Memory = u.Memory
+	}
+	if u.MemoryReservation != 0 {
+		r.MemoryReservation = u.MemoryReservation
+	}
+	if u.MemorySwap != 0 {
+		r.MemorySwap = u.MemorySwap
+	}
+	if u.PidsLimit != nil {
+		r.PidsLimit = u.PidsLimit
+	}
+	if u.BlkioWeight != 0 {
+		r.BlkioWeight = u.BlkioWeight
+	}
+	if update.RestartPolicy.Name != "" {
+		hostConfig.RestartPolicy = update.RestartPolicy
+	}
+	return hostConfig
+}
+
+// positiveInt parses a positive whole number parameter
+func positiveInt(param string, v interface{}) (int64, error) {
+	n, ok := v.(float64)
+	if !ok || n <= 0 || n != float64(int64(n)) {
+		return 0, fmt.Errorf("%s must be a positive whole number", param)
+	}
+	return int64(n), nil
+}
+
+// nonEmptyString parses a string parameter that must not be empty
+func nonEmptyString(param string, v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return "", fmt.Errorf("%s must be a non-empty string", param)
+	}
+	return s, nil
+}
+
+// memorySize parses a memory size parameter such as 512m or 2g
+func memorySize(param string, v interface{}) (int64, error) {
+	s, _ := v.(string)
+	n, err := units.RAMInBytes(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a size such as 512m or 2g", param, s)
+	}
+	return n, nil
+}
+
+// intOr formats a number, or the fallback when it is not set
+func intOr(n int64, fallback string) string {
+	if n <= 0 {
+		return fallback
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// stringOr returns s, or the fallback when it is empty
+func stringOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// bytesOr formats a byte count in binary units, or the fallback when it is not set
+func bytesOr(n int64, fallback string) string {
+	if n <= 0 {
+		return fallback
+	}
+	return units.BytesSize(float64(n))
+}
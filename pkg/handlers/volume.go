@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// HandleListVolumes handles volume listing requests
+// Supports optional 'name', 'driver' and 'dangling' filters
+func (h *Handler) HandleListVolumes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	filterArgs := filters.NewArgs()
+	if name, ok := params["name"].(string); ok && name != "" {
+		filterArgs.Add("name", name)
+	}
+	if driver, ok := params["driver"].(string); ok && driver != "" {
+		filterArgs.Add("driver", driver)
+	}
+	if dangling, ok := params["dangling"].(bool); ok {
+		filterArgs.Add("dangling", strconv.FormatBool(dangling))
+	}
+
+	resp, err := h.dockerClient.ListVolumes(ctx, filterArgs)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to list volumes: %w", err))
+	}
+
+	var result []models.VolumeInfo
+	for _, v := range resp.Volumes {
+		if v == nil {
+			continue
+		}
+		result = append(result, volumeInfo(*v))
+	}
+
+	return h.formatResponse(result)
+}
+
+// HandleInspectVolume handles volume inspection requests
+func (h *Handler) HandleInspectVolume(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	name, ok := params["name"].(string)
+	if !ok || name == "" {
+		return h.formatErrorResponse(fmt.Errorf("name is required"))
+	}
+
+	volumeInfo, err := h.dockerClient.InspectVolume(ctx, name)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to inspect volume: %w", err))
+	}
+
+	// Convert to JSON
+	details, err := json.Marshal(volumeInfo)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to serialize volume information: %w", err))
+	}
+
+	return h.formatResponse(models.InspectResponse{
+		ID:      name,
+		Type:    "volume",
+		Details: details,
+	})
+}
+
+// HandleCreateVolume handles volume creation requests
+func (h *Handler) HandleCreateVolume(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	options := volume.CreateOptions{
+		DriverOpts: stringMap(params, "driver_opts"),
+		Labels:     stringMap(params, "labels"),
+	}
+
+	// The daemon generates a name when none is given
+	if name, ok := params["name"].(string); ok {
+		options.Name = name
+	}
+	if driver, ok := params["driver"].(string); ok && driver != "" {
+		options.Driver = driver
+	}
+
+	if err := h.options.Policy.CheckVolume(options); err != nil {
+		return h.formatErrorResponse(err)
+	}
+
+	v, err := h.dockerClient.CreateVolume(ctx, options)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to create volume: %w", err))
+	}
+
+	return h.formatResponse(volumeInfo(v))
+}
+
+// HandleRemoveVolume handles volume removal requests
+func (h *Handler) HandleRemoveVolume(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	name, ok := params["name"].(string)
+	if !ok || name == "" {
+		return h.formatErrorResponse(fmt.Errorf("name is required"))
+	}
+
+	force := false
+	if forceVal, ok := params["force"].(bool); ok {
+		force = forceVal
+	}
+
+	if err := h.dockerClient.RemoveVolume(ctx, name, force); err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to remove volume: %w", err))
+	}
+
+	return h.formatResponse(models.VolumeActionResponse{
+		Name:   name,
+		Action: "remove",
+		Status: "success",
+	})
+}
+
+// HandlePruneVolumes handles requests to remove unused volumes
+func (h *Handler) HandlePruneVolumes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params := request.Params.Arguments
+
+	filterArgs := filters.NewArgs()
+	// Without 'all' the daemon only prunes anonymous volumes
+	if all, ok := params["all"].(bool); ok && all {
+		filterArgs.Add("all", "true")
+	}
+	for _, label := range stringSlice(params, "labels") {
+		filterArgs.Add("label", label)
+	}
+
+	report, err := h.dockerClient.PruneVolumes(ctx, filterArgs)
+	if err != nil {
+		return h.formatErrorResponse(fmt.Errorf("failed to prune volumes: %w", err))
+	}
+
+	deleted := report.VolumesDeleted
+	if deleted == nil {
+		deleted = []string{}
+	}
+
+	return h.formatResponse(models.PruneResponse{
+		Deleted:        deleted,
+		SpaceReclaimed: report.SpaceReclaimed,
+	})
+}
+
+// volumeInfo converts a Docker volume to its API model
+func volumeInfo(v volume.Volume) models.VolumeInfo {
+	return models.VolumeInfo{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		Scope:      v.Scope,
+		Labels:     v.Labels,
+		Options:    v.Options,
+		CreatedAt:  v.CreatedAt,
+	}
+}
+
+// parseMount converts a structured mount specification from create_container into a Docker mount
+func parseMount(spec map[string]interface{}) (mount.Mount, error) {
+	m := mount.Mount{}
+
+	mountType, _ := spec["type"].(string)
+	m.Type = mount.Type(mountType)
+	m.Source, _ = spec["source"].(string)
+	m.Target, _ = spec["target"].(string)
+	if readOnly, ok := spec["read_only"].(bool); ok {
+		m.ReadOnly = readOnly
+	}
+
+	if m.Target == "" {
+		return m, fmt.Errorf("mount target is required")
+	}
+
+	switch m.Type {
+	case mount.TypeBind:
+		if m.Source == "" {
+			return m, fmt.Errorf("bind mount %s requires a source path", m.Target)
+		}
+		if propagation, ok := spec["propagation"].(string); ok && propagation != "" {
+			if !validPropagation(propagation) {
+				return m, fmt.Errorf("invalid propagation %q for mount %s", propagation, m.Target)
+			}
+			m.BindOptions = &mount.BindOptions{Propagation: mount.Propagation(propagation)}
+		}
+	case mount.TypeVolume:
+		// An empty source creates an anonymous volume
+		opts := &mount.VolumeOptions{
+			Labels: stringMap(spec, "volume_labels"),
+		}
+		if noCopy, ok := spec["no_copy"].(bool); ok {
+			opts.NoCopy = noCopy
+		}
+		if driver, ok := spec["volume_driver"].(string); ok && driver != "" {
+			opts.DriverConfig = &mount.Driver{
+				Name:    driver,
+				Options: stringMap(spec, "volume_driver_opts"),
+			}
+		}
+		m.VolumeOptions = opts
+	case mount.TypeTmpfs:
+		if m.Source != "" {
+			return m, fmt.Errorf("tmpfs mount %s must not have a source", m.Target)
+		}
+		opts := &mount.TmpfsOptions{}
+		if size, ok := spec["tmpfs_size"].(float64); ok {
+			opts.SizeBytes = int64(size)
+		}
+		if mode, ok := spec["tmpfs_mode"].(string); ok && mode != "" {
+			parsed, err := strconv.ParseUint(mode, 8, 32)
+			if err != nil {
+				return m, fmt.Errorf("invalid tmpfs_mode %q for mount %s: expected octal", mode, m.Target)
+			}
+			opts.Mode = os.FileMode(parsed)
+		}
+		m.TmpfsOptions = opts
+	default:
+		return m, fmt.Errorf("unsupported mount type %q for mount %s (use bind, volume or tmpfs)", mountType, m.Target)
+	}
+
+	return m, nil
+}
+
+// mounts collects the structured 'mounts' parameter of create_container
+func mounts(params map[string]interface{}) ([]mount.Mount, error) {
+	var result []mount.Mount
+
+	if mountArray, ok := params["mounts"].([]interface{}); ok {
+		for _, item := range mountArray {
+			spec, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid mount specification: %v", item)
+			}
+			m, err := parseMount(spec)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, m)
+		}
+	}
+
+	return result, nil
+}
+
+// validPropagation reports whether p is a known bind propagation mode
+func validPropagation(p string) bool {
+	for _, valid := range mount.Propagations {
+		if string(valid) == p {
+			return true
+		}
+	}
+	return false
+}
@@ -54,16 +54,58 @@ type SearchResult struct {
 
 // ContainerConfig represents container creation configuration
 type ContainerConfig struct {
-	Name          string            `json:"name"`                     // Container name
-	Image         string            `json:"image"`                    // Image to use
-	Command       []string          `json:"command,omitempty"`        // Command to run
-	Env           []string          `json:"env,omitempty"`            // Environment variables
-	Ports         map[string]string `json:"ports,omitempty"`          // Port mappings
-	Volumes       []string          `json:"volumes,omitempty"`        // Volume mappings
-	WorkingDir    string            `json:"working_dir,omitempty"`    // Working directory
-	NetworkMode   string            `json:"network_mode,omitempty"`   // Network mode
-	RestartPolicy string            `json:"restart_policy,omitempty"` // Restart policy
-	AutoRemove    bool              `json:"auto_remove,omitempty"`    // Auto-remove when stopped
+	Name              string             `json:"name"`                          // Container name
+	Image             string             `json:"image"`                         // Image to use
+	Entrypoint        []string           `json:"entrypoint,omitempty"`          // Entrypoint overriding the image's
+	Command           []string           `json:"command,omitempty"`             // Command to run
+	Env               []string           `json:"env,omitempty"`                 // Environment variables
+	User              string             `json:"user,omitempty"`                // User to run as
+	Hostname          string             `json:"hostname,omitempty"`            // Container host name
+	Labels            map[string]string  `json:"labels,omitempty"`              // Container labels
+	Ports             map[string]string  `json:"ports,omitempty"`               // Port mappings
+	Volumes           []string           `json:"volumes,omitempty"`             // Volume mappings
+	Tmpfs             []string           `json:"tmpfs,omitempty"`               // tmpfs mounts as path[:options]
+	ReadOnly          bool               `json:"read_only,omitempty"`           // Read-only root filesystem
+	WorkingDir        string             `json:"working_dir,omitempty"`         // Working directory
+	NetworkMode       string             `json:"network_mode,omitempty"`        // Network mode
+	ExtraHosts        []string           `json:"extra_hosts,omitempty"`         // Additional /etc/hosts entries as host:ip
+	DNS               []string           `json:"dns,omitempty"`                 // DNS servers
+	DNSSearch         []string           `json:"dns_search,omitempty"`          // DNS search domains
+	DNSOptions        []string           `json:"dns_options,omitempty"`         // DNS resolver options
+	RestartPolicy     string             `json:"restart_policy,omitempty"`      // Restart policy
+	RestartMaxRetries int                `json:"restart_max_retries,omitempty"` // Retries of the on-failure restart policy
+	AutoRemove        bool               `json:"auto_remove,omitempty"`         // Auto-remove when stopped
+	Memory            string             `json:"memory,omitempty"`              // Memory limit such as 512m
+	MemoryReservation string             `json:"memory_reservation,omitempty"`  // Memory soft limit
+	MemorySwap        string             `json:"memory_swap,omitempty"`         // Memory plus swap limit, or -1 for unlimited
+	CPUs              float64            `json:"cpus,omitempty"`                // Number of CPUs
+	CPUShares         int64              `json:"cpu_shares,omitempty"`          // Relative CPU weight
+	CPUPeriod         int64              `json:"cpu_period,omitempty"`          // CFS period in microseconds
+	CPUQuota          int64              `json:"cpu_quota,omitempty"`           // CFS quota in microseconds
+	CpusetCpus        string             `json:"cpuset_cpus,omitempty"`         // CPUs the container may run on
+	CpusetMems        string             `json:"cpuset_mems,omitempty"`         // Memory nodes the container may use
+	PidsLimit         int64              `json:"pids_limit,omitempty"`          // Maximum number of processes
+	BlkioWeight       uint16             `json:"blkio_weight,omitempty"`        // Relative block I/O weight
+	Ulimits           map[string]string  `json:"ulimits,omitempty"`             // Resource limits as soft[:hard] by name
+	Healthcheck       *HealthcheckConfig `json:"healthcheck,omitempty"`         // Healthcheck overriding the image's
+	CapAdd            []string           `json:"cap_add,omitempty"`             // Capabilities to add
+	CapDrop           []string           `json:"cap_drop,omitempty"`            // Capabilities to drop
+	SecurityOpt       []string           `json:"security_opt,omitempty"`        // Security options
+	StopSignal        string             `json:"stop_signal,omitempty"`         // Signal sent to stop the container
+	StopTimeout       *int               `json:"stop_timeout,omitempty"`        // Seconds to wait before killing on stop
+	LogDriver         string             `json:"log_driver,omitempty"`          // Logging driver
+	LogOpts           map[string]string  `json:"log_opts,omitempty"`            // Logging driver options
+}
+
+// HealthcheckConfig represents a container healthcheck definition
+type HealthcheckConfig struct {
+	Test          []string `json:"test,omitempty"`           // Check command; a single string runs in the shell
+	Interval      string   `json:"interval,omitempty"`       // Time between checks, such as 30s
+	Timeout       string   `json:"timeout,omitempty"`        // Time a check may take
+	StartPeriod   string   `json:"start_period,omitempty"`   // Initialization time in which failures are not counted
+	StartInterval string   `json:"start_interval,omitempty"` // Time between checks during the start period
+	Retries       int      `json:"retries,omitempty"`        // Consecutive failures before the container is unhealthy
+	Disable       bool     `json:"disable,omitempty"`        // Turn off the image's healthcheck
 }
 
 // ContainerCreatedResponse represents the response after creating a container
@@ -79,6 +121,40 @@ type ContainerActionResponse struct {
 	Status string `json:"status"` // Operation status
 }
 
+// ContainerUpdateResponse represents the outcome of updating a container's settings in place
+type ContainerUpdateResponse struct {
+	ContainerID string          `json:"container_id"`       // Container ID
+	Changes     []SettingChange `json:"changes"`            // Requested settings with their values before and after
+	Warnings    []string        `json:"warnings,omitempty"` // Warnings reported by the daemon
+}
+
+// SettingChange represents a container setting changed by an update
+type SettingChange struct {
+	Setting string `json:"setting"` // Setting name, as given to update_container
+	Before  string `json:"before"`  // Value before the update
+	After   string `json:"after"`   // Value applied by the daemon
+}
+
+// ContainerWaitResponse represents the outcome of waiting for a container
+type ContainerWaitResponse struct {
+	ContainerID string `json:"container_id"`    // Container ID
+	Condition   string `json:"condition"`       // Condition waited for (not-running, next-exit or removed)
+	ExitCode    int64  `json:"exit_code"`       // Exit code of the container (-1 if the wait timed out)
+	Error       string `json:"error,omitempty"` // Error reported by the daemon for the exit, e.g. a failed start
+	TimedOut    bool   `json:"timed_out"`       // Whether the timeout expired before the condition was met
+}
+
+// DryRunResponse describes what a mutating tool would do, returned instead of acting when dry_run is set
+type DryRunResponse struct {
+	DryRun   bool     `json:"dry_run"`            // Always true
+	Action   string   `json:"action"`             // Action that would be performed
+	ID       string   `json:"id,omitempty"`       // Resolved ID of the object acted on
+	Name     string   `json:"name,omitempty"`     // Name of the object acted on
+	State    string   `json:"state,omitempty"`    // Current container state
+	Plan     []string `json:"plan"`               // Steps that would be performed, in order
+	Warnings []string `json:"warnings,omitempty"` // Side effects worth reviewing before running for real
+}
+
 // ImageRemovedResponse represents the response after removing an image
 type ImageRemovedResponse struct {
 	Removed     bool     `json:"removed"`                // Whether image was removed
@@ -88,23 +164,58 @@ type ImageRemovedResponse struct {
 
 // LogsResponse represents container logs response
 type LogsResponse struct {
-	ContainerID string `json:"container_id"` // Container ID
-	Logs        string `json:"logs"`         // Container logs
+	ContainerID  string             `json:"container_id"`            // Container ID
+	Stdout       string             `json:"stdout,omitempty"`        // Standard output lines (split format)
+	Stderr       string             `json:"stderr,omitempty"`        // Standard error lines (split format)
+	Lines        []LogLine          `json:"lines,omitempty"`         // Lines tagged with their stream (tagged format)
+	LineCount    int                `json:"line_count"`              // Number of lines returned
+	Truncated    bool               `json:"truncated"`               // Whether earlier lines were dropped by max_lines/max_bytes
+	OmittedLines int                `json:"omitted_lines,omitempty"` // Number of dropped lines
+	Follow       *LogsFollowSummary `json:"follow,omitempty"`        // Summary of a follow session
+}
+
+// LogsFollowSummary describes a logs follow session whose lines were streamed as notifications
+type LogsFollowSummary struct {
+	StreamedLines   int     `json:"streamed_lines"`   // Lines sent to the client
+	DurationSeconds float64 `json:"duration_seconds"` // How long the logs were followed
+	StopReason      string  `json:"stop_reason"`      // duration, line_limit, ended (stream closed) or cancelled
+}
+
+// LogLine represents a single line of container output
+type LogLine struct {
+	Stream string `json:"stream"` // Output stream (stdout/stderr), or "truncated" for the truncation marker
+	Text   string `json:"text"`   // Line content
 }
 
 // BuildImageResponse represents image build operation response
 type BuildImageResponse struct {
-	Success bool     `json:"success"`            // Build success status
-	ImageID string   `json:"image_id,omitempty"` // Built image ID
-	Tags    []string `json:"tags,omitempty"`     // Image tags
-	Error   string   `json:"error,omitempty"`    // Error message if build failed
+	Success bool        `json:"success"`            // Build success status
+	ImageID string      `json:"image_id,omitempty"` // Built image ID
+	Tags    []string    `json:"tags,omitempty"`     // Image tags
+	Steps   []BuildStep `json:"steps,omitempty"`    // Per-step build log, from legacy builder output or BuildKit traces
+
+	ContextSize  int64 `json:"context_size,omitempty"`  // Bytes of file content uploaded as the build context
+	ContextFiles int   `json:"context_files,omitempty"` // Number of files uploaded as the build context
+	IgnoredFiles int   `json:"ignored_files,omitempty"` // Files left out by .dockerignore
+}
+
+// BuildStep represents one Dockerfile instruction executed during a build
+type BuildStep struct {
+	Step        string `json:"step"`             // Position in the build, e.g. "2/5"
+	Instruction string `json:"instruction"`      // Dockerfile instruction
+	Cached      bool   `json:"cached,omitempty"` // Whether the result came from the build cache
+	Output      string `json:"output,omitempty"` // Output produced while running the step
 }
 
 // CommandResponse represents command execution response
 type CommandResponse struct {
 	ContainerID string `json:"container_id"` // Container ID
 	Command     string `json:"command"`      // Executed command
-	Output      string `json:"output"`       // Command output
+	Stdout      string `json:"stdout"`       // Standard output
+	Stderr      string `json:"stderr"`       // Standard error
+	ExitCode    int    `json:"exit_code"`    // Exit code of the command (-1 if still running)
+	TimedOut    bool   `json:"timed_out"`    // Whether the timeout expired before the command finished
+	Killed      bool   `json:"killed"`       // Whether the command was killed because the timeout expired
 }
 
 // InspectResponse represents detailed inspection response
@@ -114,11 +225,75 @@ type InspectResponse struct {
 	Details json.RawMessage `json:"details"` // Detailed information
 }
 
+// ContainerStatsResponse represents resource usage sampled from one or more containers
+type ContainerStatsResponse struct {
+	SampleSeconds float64          `json:"sample_seconds"` // Length of the window the rates were measured over
+	Containers    []ContainerStats `json:"containers"`     // Usage per container
+}
+
+// ContainerStats represents the resource usage of a single container
+type ContainerStats struct {
+	ID              string  `json:"id"`                        // Container ID
+	Name            string  `json:"name,omitempty"`            // Container name
+	CPUPercent      float64 `json:"cpu_percent"`               // CPU usage over the window; 100 is one full CPU
+	MemoryUsage     uint64  `json:"memory_usage"`              // Memory in use in bytes, excluding reclaimable page cache
+	MemoryLimit     uint64  `json:"memory_limit"`              // Memory limit in bytes (host memory if unlimited)
+	MemoryPercent   float64 `json:"memory_percent"`            // Memory usage as a percentage of the limit
+	NetworkRxBytes  uint64  `json:"network_rx_bytes"`          // Total bytes received on all interfaces
+	NetworkTxBytes  uint64  `json:"network_tx_bytes"`          // Total bytes sent on all interfaces
+	NetworkRxRate   float64 `json:"network_rx_bytes_per_sec"`  // Receive rate over the window
+	NetworkTxRate   float64 `json:"network_tx_bytes_per_sec"`  // Send rate over the window
+	BlockReadBytes  uint64  `json:"block_read_bytes"`          // Total bytes read from block devices
+	BlockWriteBytes uint64  `json:"block_write_bytes"`         // Total bytes written to block devices
+	BlockReadRate   float64 `json:"block_read_bytes_per_sec"`  // Block read rate over the window
+	BlockWriteRate  float64 `json:"block_write_bytes_per_sec"` // Block write rate over the window
+	PIDs            uint64  `json:"pids"`                      // Number of processes and threads
+	Error           string  `json:"error,omitempty"`           // Why the container could not be sampled
+}
+
+// ContainerTopResponse represents the processes running in a container
+type ContainerTopResponse struct {
+	ContainerID string              `json:"container_id"` // Container ID
+	Titles      []string            `json:"titles"`       // ps column titles, in output order
+	Processes   []map[string]string `json:"processes"`    // One row per process, keyed by column title
+}
+
+// ContainerDiffResponse represents the filesystem changes in a container since it was created
+type ContainerDiffResponse struct {
+	ContainerID string   `json:"container_id"` // Container ID
+	Added       []string `json:"added"`        // Paths added
+	Changed     []string `json:"changed"`      // Paths modified
+	Deleted     []string `json:"deleted"`      // Paths deleted
+	Total       int      `json:"total"`        // Number of matching changes, including any dropped by max_entries
+	Truncated   bool     `json:"truncated"`    // Whether changes were dropped by max_entries
+}
+
+// ContainerPortResponse represents the host bindings of a container's ports
+type ContainerPortResponse struct {
+	ContainerID string        `json:"container_id"` // Container ID
+	Ports       []ExposedPort `json:"ports"`        // Exposed and published ports, sorted by port
+}
+
+// ExposedPort represents a container port and the host addresses it is published on
+type ExposedPort struct {
+	Port     string        `json:"port"`     // Container port and protocol, e.g. "80/tcp"
+	Bindings []HostBinding `json:"bindings"` // Host addresses; empty if the port is not published or the container is not running
+}
+
+// HostBinding represents a host address a container port is published on
+type HostBinding struct {
+	HostIP   string `json:"host_ip"`   // Host IP address
+	HostPort string `json:"host_port"` // Host port
+}
+
 // PullProgressResponse represents image pull progress
 type PullProgressResponse struct {
-	ImageName string `json:"image_name"` // Image being pulled
-	Status    string `json:"status"`     // Current status
-	Complete  bool   `json:"complete"`   // Whether pull is complete
+	ImageName string `json:"image_name"`        // Image being pulled
+	Status    string `json:"status"`            // Current status
+	Complete  bool   `json:"complete"`          // Whether pull is complete
+	Digest    string `json:"digest,omitempty"`  // Content digest of the pulled image
+	Message   string `json:"message,omitempty"` // Final status reported by the daemon
+	Layers    int    `json:"layers"`            // Number of layers in the image
 }
 
 // ProgressEvent represents an image pull progress event
@@ -128,5 +303,63 @@ type ProgressEvent struct {
 		Current int64 `json:"current"` // Current progress
 		Total   int64 `json:"total"`   // Total size
 	} `json:"progressDetail"`
-	ID string `json:"id"` // Layer ID
+	ID          string `json:"id"`    // Layer ID
+	Error       string `json:"error"` // Error message if the operation failed
+	ErrorDetail *struct {
+		Code    int    `json:"code"`    // Error code
+		Message string `json:"message"` // Error message
+	} `json:"errorDetail,omitempty"` // Structured error details
+}
+
+// NetworkInfo represents summary information about a Docker network
+type NetworkInfo struct {
+	ID         string            `json:"id"`                // Network ID
+	Name       string            `json:"name"`              // Network name
+	Driver     string            `json:"driver"`            // Network driver (bridge, overlay, ...)
+	Scope      string            `json:"scope"`             // Network scope (local, swarm)
+	Internal   bool              `json:"internal"`          // Whether the network is internal only
+	Attachable bool              `json:"attachable"`        // Whether containers can be attached manually
+	EnableIPv6 bool              `json:"enable_ipv6"`       // Whether IPv6 is enabled
+	Subnets    []string          `json:"subnets,omitempty"` // Configured subnets
+	Labels     map[string]string `json:"labels,omitempty"`  // Network labels
+	Created    time.Time         `json:"created"`           // Creation time
+}
+
+// NetworkCreatedResponse represents the response after creating a network
+type NetworkCreatedResponse struct {
+	ID      string `json:"id"`                // Created network ID
+	Name    string `json:"name"`              // Network name
+	Warning string `json:"warning,omitempty"` // Warning returned by the daemon
+}
+
+// NetworkActionResponse represents the response for network operations
+type NetworkActionResponse struct {
+	NetworkID   string `json:"network_id"`             // Network ID or name
+	ContainerID string `json:"container_id,omitempty"` // Container ID for connect/disconnect
+	Action      string `json:"action"`                 // Action performed
+	Status      string `json:"status"`                 // Operation status
+}
+
+// PruneResponse represents the result of a prune operation
+type PruneResponse struct {
+	Deleted        []string `json:"deleted"`                   // Names or IDs of removed objects
+	SpaceReclaimed uint64   `json:"space_reclaimed,omitempty"` // Disk space reclaimed in bytes
+}
+
+// VolumeInfo represents information about a Docker volume
+type VolumeInfo struct {
+	Name       string            `json:"name"`                 // Volume name
+	Driver     string            `json:"driver"`               // Volume driver
+	Mountpoint string            `json:"mountpoint"`           // Mount path on the host
+	Scope      string            `json:"scope"`                // Volume scope (local, global)
+	Labels     map[string]string `json:"labels,omitempty"`     // Volume labels
+	Options    map[string]string `json:"options,omitempty"`    // Driver options
+	CreatedAt  string            `json:"created_at,omitempty"` // Creation time
+}
+
+// VolumeActionResponse represents the response for volume operations
+type VolumeActionResponse struct {
+	Name   string `json:"name"`   // Volume name
+	Action string `json:"action"` // Action performed
+	Status string `json:"status"` // Operation status
 }
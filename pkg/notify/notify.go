@@ -0,0 +1,100 @@
+// Package notify delivers server-to-client MCP notifications (progress and
+// log messages) for the request in progress. Transports attach a Notifier to
+// the request context; tool handlers send through the helpers in this package.
+package notify
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MCP notification methods
+const (
+	MethodProgress = "notifications/progress"
+	MethodMessage  = "notifications/message"
+)
+
+// Notifier sends a notification to the client that issued the current request
+type Notifier interface {
+	Notify(method string, params map[string]interface{}) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(method string, params map[string]interface{}) error
+
+// Notify implements Notifier
+func (f NotifierFunc) Notify(method string, params map[string]interface{}) error {
+	return f(method, params)
+}
+
+// Discard is a notifier that drops every notification, for transports that cannot deliver them
+var Discard Notifier = NotifierFunc(func(string, map[string]interface{}) error { return nil })
+
+// notifierKey is the context key for the request's notifier
+type notifierKey struct{}
+
+// NewContext returns a copy of ctx carrying the notifier
+func NewContext(ctx context.Context, n Notifier) context.Context {
+	return context.WithValue(ctx, notifierKey{}, n)
+}
+
+// FromContext returns the notifier for the current request. Without one
+// attached by the transport it falls back to the MCP server's own client
+// notification channel (used by stdio), or nil when neither is available.
+func FromContext(ctx context.Context) Notifier {
+	if n, ok := ctx.Value(notifierKey{}).(Notifier); ok && n != nil {
+		return n
+	}
+	if srv := server.ServerFromContext(ctx); srv != nil {
+		return NotifierFunc(srv.SendNotificationToClient)
+	}
+	return nil
+}
+
+// Progress reports progress on the request identified by token. It does
+// nothing when the client did not ask for progress (token is nil).
+func Progress(ctx context.Context, token mcp.ProgressToken, progress, total float64, message string) error {
+	n := FromContext(ctx)
+	if n == nil || token == nil {
+		return nil
+	}
+
+	params := map[string]interface{}{
+		"progressToken": token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	return n.Notify(MethodProgress, params)
+}
+
+// Log sends a log message notification
+func Log(ctx context.Context, level mcp.LoggingLevel, logger string, data interface{}) error {
+	n := FromContext(ctx)
+	if n == nil {
+		return nil
+	}
+
+	params := map[string]interface{}{
+		"level": level,
+		"data":  data,
+	}
+	if logger != "" {
+		params["logger"] = logger
+	}
+	return n.Notify(MethodMessage, params)
+}
+
+// ProgressToken returns the progress token sent with a tool call, or nil
+func ProgressToken(request mcp.CallToolRequest) mcp.ProgressToken {
+	if request.Params.Meta == nil {
+		return nil
+	}
+	return request.Params.Meta.ProgressToken
+}
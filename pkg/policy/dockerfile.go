@@ -0,0 +1,97 @@
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// baseImages returns the images a Dockerfile builds FROM. References to
+// earlier build stages and scratch are skipped. Variables are expanded from
+// the ARG instructions before the first FROM, overridden by buildArgs.
+func baseImages(dockerfile []byte, buildArgs map[string]*string) ([]string, error) {
+	args := make(map[string]string)
+	stages := make(map[string]bool)
+	var images []string
+	seenFrom := false
+
+	for _, line := range instructions(dockerfile) {
+		keyword, rest, _ := strings.Cut(line, " ")
+		fields := strings.Fields(rest)
+
+		switch strings.ToUpper(keyword) {
+		case "ARG":
+			// Only global ARGs, declared before the first FROM, apply to FROM lines
+			if seenFrom {
+				continue
+			}
+			for _, field := range fields {
+				name, value, _ := strings.Cut(field, "=")
+				args[name] = strings.Trim(value, `"'`)
+				if override, ok := buildArgs[name]; ok && override != nil {
+					args[name] = *override
+				}
+			}
+		case "FROM":
+			seenFrom = true
+
+			var ref, stage string
+			for i, field := range fields {
+				if strings.HasPrefix(field, "--") {
+					continue
+				}
+				ref = field
+				if i+2 < len(fields) && strings.EqualFold(fields[i+1], "AS") {
+					stage = strings.ToLower(fields[i+2])
+				}
+				break
+			}
+			if ref == "" {
+				return images, fmt.Errorf("dockerfile has a FROM instruction without an image")
+			}
+
+			image := os.Expand(ref, func(name string) string { return args[name] })
+			if image == "" || strings.Contains(image, "$") {
+				return images, fmt.Errorf("base image %s cannot be resolved", ref)
+			}
+			if !strings.EqualFold(image, "scratch") && !stages[strings.ToLower(image)] {
+				images = append(images, image)
+			}
+			if stage != "" {
+				stages[stage] = true
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// instructions splits a Dockerfile into instructions, joining continuation
+// lines and dropping comments and blank lines
+func instructions(dockerfile []byte) []string {
+	var result []string
+	var current strings.Builder
+
+	scanner := bufio.NewScanner(bytes.NewReader(dockerfile))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasSuffix(line, "\\") {
+			current.WriteString(strings.TrimSuffix(line, "\\"))
+			current.WriteString(" ")
+			continue
+		}
+		current.WriteString(line)
+		result = append(result, current.String())
+		current.Reset()
+	}
+	if current.Len() > 0 {
+		result = append(result, current.String())
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBaseImages(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		buildArgs  map[string]*string
+		images     []string
+		err        string
+	}{
+		{
+			name:       "stages and scratch",
+			dockerfile: "FROM golang:1.23 AS Build\nRUN make\nFROM build AS test\nFROM alpine\nFROM scratch\n",
+			images:     []string{"golang:1.23", "alpine"},
+		},
+		{
+			name:       "global args",
+			dockerfile: "ARG BASE=\"busybox\"\nARG TAG=1.36\nFROM ${BASE}:$TAG\nARG BASE=ignored\nFROM $BASE\n",
+			images:     []string{"busybox:1.36", "busybox"},
+		},
+		{
+			name:       "build arg override",
+			dockerfile: "ARG BASE=busybox\nFROM ${BASE}\n",
+			buildArgs:  map[string]*string{"BASE": ptr("alpine")},
+			images:     []string{"alpine"},
+		},
+		{
+			name:       "flags, comments and continuations",
+			dockerfile: "# syntax=docker/dockerfile:1\n\nFROM --platform=linux/amd64 \\\n  nginx:latest\n",
+			images:     []string{"nginx:latest"},
+		},
+		{
+			name:       "unset variable",
+			dockerfile: "FROM ${UNSET}\n",
+			err:        "base image ${UNSET} cannot be resolved",
+		},
+		{
+			name:       "missing image",
+			dockerfile: "FROM --platform=linux/arm64\n",
+			err:        "dockerfile has a FROM instruction without an image",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images, err := baseImages([]byte(tt.dockerfile), tt.buildArgs)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(images, tt.images) {
+				t.Errorf("got %v, want %v", images, tt.images)
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+// Package policy evaluates container creation, exec, volume creation and image
+// builds against a declarative safety policy loaded from a YAML or JSON file.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy restricts what the container, exec and build tools may do. The zero
+// value allows everything, and so does a nil *Policy: every Check method may
+// be called on nil, so callers need no guard.
+type Policy struct {
+	DeniedHostPaths     []string       `yaml:"denied_host_paths"`    // Glob patterns of host paths that must not be bind mounted
+	DenyHostNetwork     bool           `yaml:"deny_host_network"`    // Reject the host network mode for containers and builds
+	DenyPrivileged      bool           `yaml:"deny_privileged"`      // Reject privileged containers, privileged exec and security options that lift confinement
+	DeniedCapabilities  []string       `yaml:"denied_capabilities"`  // Capabilities that must not be added; ALL rejects any
+	AllowedRegistries   []string       `yaml:"allowed_registries"`   // Registries images may come from, e.g. docker.io
+	AllowedRepositories []string       `yaml:"allowed_repositories"` // Glob patterns of repositories, e.g. docker.io/library/*
+	RequireLimits       ResourceLimits `yaml:"require_limits"`       // Resource limits every new container must set
+}
+
+// ResourceLimits selects the limits a container must set
+type ResourceLimits struct {
+	Memory bool `yaml:"memory"` // Memory limit
+	CPU    bool `yaml:"cpu"`    // CPU quota or CPU count
+	Pids   bool `yaml:"pids"`   // Process count limit
+}
+
+// Violation is returned when a request breaks the policy. It lists every
+// rule broken so the caller can fix them all at once.
+type Violation struct {
+	Action  string   // What was denied, e.g. "container creation"
+	Reasons []string // Rules the request breaks
+}
+
+// Error implements the error interface
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s denied by policy: %s", v.Action, strings.Join(v.Reasons, "; "))
+}
+
+// Load reads a policy from a YAML or JSON file:
+//
+//	denied_host_paths: [/var/run/docker.sock, /etc, /root/*]
+//	deny_host_network: true
+//	deny_privileged: true
+//	denied_capabilities: [SYS_ADMIN, NET_ADMIN]
+//	allowed_registries: [ghcr.io]
+//	allowed_repositories: [docker.io/library/*]
+//	require_limits: {memory: true, pids: true}
+func Load(filePath string) (*Policy, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open policy: %w", err)
+	}
+	defer f.Close()
+
+	var p Policy
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", filePath, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", filePath, err)
+	}
+	return &p, nil
+}
+
+// validate checks the patterns and normalizes capability names
+func (p *Policy) validate() error {
+	for _, pattern := range p.DeniedHostPaths {
+		if !path.IsAbs(pattern) {
+			return fmt.Errorf("denied host path %q must be absolute", pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("denied host path %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range p.AllowedRepositories {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("allowed repository %q: %w", pattern, err)
+		}
+	}
+	for i, capability := range p.DeniedCapabilities {
+		p.DeniedCapabilities[i] = normalizeCapability(capability)
+	}
+	return nil
+}
+
+// CheckCreate evaluates a container about to be created. joined is the network
+// mode of the container whose network namespace it joins through a
+// container:<id> network mode, resolved by the caller; it is empty otherwise.
+func (p *Policy) CheckCreate(config *container.Config, hostConfig *container.HostConfig, joined container.NetworkMode) error {
+	if p == nil {
+		return nil
+	}
+
+	reasons := p.imageReasons(config.Image)
+	reasons = append(reasons, p.hostReasons(hostConfig)...)
+	if p.DenyHostNetwork && joined.IsHost() {
+		reasons = append(reasons, fmt.Sprintf("network mode %s shares the host network", hostConfig.NetworkMode))
+	}
+
+	for _, limit := range p.missingLimits(hostConfig.Resources) {
+		reasons = append(reasons, fmt.Sprintf("a %s limit is required", limit))
+	}
+
+	return violation("container creation", reasons)
+}
+
+// CheckUpdate evaluates a change to a container's resources. An update may
+// not remove a required limit the container has, so containers created
+// before the policy can still be updated.
+func (p *Policy) CheckUpdate(before, after container.Resources) error {
+	if p == nil {
+		return nil
+	}
+
+	missing := make(map[string]bool)
+	for _, limit := range p.missingLimits(before) {
+		missing[limit] = true
+	}
+	var reasons []string
+	for _, limit := range p.missingLimits(after) {
+		if !missing[limit] {
+			reasons = append(reasons, fmt.Sprintf("the required %s limit cannot be removed", limit))
+		}
+	}
+
+	return violation("container update", reasons)
+}
+
+// missingLimits returns the required limits the resources do not set
+func (p *Policy) missingLimits(resources container.Resources) []string {
+	var missing []string
+	if p.RequireLimits.Memory && resources.Memory <= 0 {
+		missing = append(missing, "memory")
+	}
+	if p.RequireLimits.CPU && resources.NanoCPUs <= 0 && resources.CPUQuota <= 0 {
+		missing = append(missing, "CPU")
+	}
+	if p.RequireLimits.Pids && (resources.PidsLimit == nil || *resources.PidsLimit <= 0) {
+		missing = append(missing, "pids")
+	}
+	return missing
+}
+
+// CheckExec evaluates an exec into an existing container. The container's own
+// host settings are checked too, so the policy cannot be bypassed by exec'ing
+// into a container created outside the server.
+func (p *Policy) CheckExec(info types.ContainerJSON, privileged bool) error {
+	if p == nil {
+		return nil
+	}
+
+	var reasons []string
+	if p.DenyPrivileged && privileged {
+		reasons = append(reasons, "privileged exec is not allowed")
+	}
+	if info.ContainerJSONBase != nil && info.HostConfig != nil {
+		for _, reason := range p.hostReasons(info.HostConfig) {
+			reasons = append(reasons, "target container: "+reason)
+		}
+	}
+
+	return violation("exec", reasons)
+}
+
+// CheckVolume evaluates a volume about to be created. Local volumes whose
+// options bind a host directory are held to the denied host paths.
+func (p *Policy) CheckVolume(options volume.CreateOptions) error {
+	if p == nil {
+		return nil
+	}
+
+	var reasons []string
+	if source, ok := localBindDevice(options.Driver, options.DriverOpts); ok {
+		if pattern, denied := p.hostPathDenied(source); denied {
+			reasons = append(reasons, fmt.Sprintf("bind mount of %s is not allowed (denied host path %s)", source, pattern))
+		}
+	}
+
+	return violation("volume creation", reasons)
+}
+
+// CheckBuild evaluates an image build. dockerfile is the Dockerfile content
+// of a local context, used to check base images; it is nil for remote contexts,
+// whose base images cannot be checked.
+func (p *Policy) CheckBuild(options types.ImageBuildOptions, dockerfile []byte) error {
+	if p == nil {
+		return nil
+	}
+
+	var reasons []string
+	if p.DenyHostNetwork && container.NetworkMode(options.NetworkMode).IsHost() {
+		reasons = append(reasons, "host network mode is not allowed")
+	}
+
+	if p.restrictsImages() {
+		if dockerfile == nil {
+			reasons = append(reasons, "base images of a remote build context cannot be verified")
+		} else {
+			images, err := baseImages(dockerfile, options.BuildArgs)
+			if err != nil {
+				reasons = append(reasons, err.Error())
+			}
+			for _, img := range images {
+				reasons = append(reasons, p.imageReasons(img)...)
+			}
+		}
+	}
+
+	return violation("build", reasons)
+}
+
+// hostReasons checks the settings that give a container access to the host
+func (p *Policy) hostReasons(hostConfig *container.HostConfig) []string {
+	var reasons []string
+
+	if p.DenyHostNetwork && hostConfig.NetworkMode.IsHost() {
+		reasons = append(reasons, "host network mode is not allowed")
+	}
+	if p.DenyPrivileged && hostConfig.Privileged {
+		reasons = append(reasons, "privileged mode is not allowed")
+	}
+	if p.DenyPrivileged {
+		for _, opt := range hostConfig.SecurityOpt {
+			if unconfined(opt) {
+				reasons = append(reasons, fmt.Sprintf("security option %s is not allowed", opt))
+			}
+		}
+	}
+	for _, capability := range hostConfig.CapAdd {
+		if p.capabilityDenied(normalizeCapability(capability)) {
+			reasons = append(reasons, fmt.Sprintf("capability %s is not allowed", normalizeCapability(capability)))
+		}
+	}
+
+	for _, source := range bindSources(hostConfig) {
+		if pattern, denied := p.hostPathDenied(source); denied {
+			reasons = append(reasons, fmt.Sprintf("bind mount of %s is not allowed (denied host path %s)", source, pattern))
+		}
+	}
+
+	return reasons
+}
+
+// imageReasons checks an image reference against the allowed registries and repositories
+func (p *Policy) imageReasons(ref string) []string {
+	if !p.restrictsImages() {
+		return nil
+	}
+
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return []string{fmt.Sprintf("image %s is not a repository reference that can be verified", ref)}
+	}
+
+	domain := reference.Domain(named)
+	for _, registry := range p.AllowedRegistries {
+		if strings.EqualFold(registry, domain) {
+			return nil
+		}
+	}
+	for _, pattern := range p.AllowedRepositories {
+		if ok, _ := path.Match(pattern, named.Name()); ok {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("image %s is not from an allowed registry or repository", ref)}
+}
+
+// restrictsImages reports whether the policy limits where images may come from
+func (p *Policy) restrictsImages() bool {
+	return len(p.AllowedRegistries) > 0 || len(p.AllowedRepositories) > 0
+}
+
+// capabilityDenied reports whether adding the normalized capability is denied
+func (p *Policy) capabilityDenied(capability string) bool {
+	for _, denied := range p.DeniedCapabilities {
+		// ALL on either side covers every capability
+		if denied == capability || denied == "ALL" || capability == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// hostPathAliases are host directories that are symlinks to one another on
+// common distributions, so a denied path can be reached under either name
+var hostPathAliases = [][2]string{
+	{"/var/run", "/run"},
+	{"/var/lock", "/run/lock"},
+}
+
+// hostPathDenied reports whether binding source exposes a denied host path.
+// A path is denied when it or one of its parent directories matches a
+// pattern, or when it contains a path matching a pattern, so denying
+// /var/run/docker.sock also rules out mounting /var or /. The path is also
+// checked under its aliases, so /run/docker.sock and /run are denied too.
+func (p *Policy) hostPathDenied(source string) (string, bool) {
+	for _, candidate := range aliasedPaths(path.Clean(source)) {
+		for _, pattern := range p.DeniedHostPaths {
+			for dir := candidate; ; dir = path.Dir(dir) {
+				if ok, _ := path.Match(pattern, dir); ok {
+					return pattern, true
+				}
+				if dir == "/" {
+					break
+				}
+			}
+
+			for parent := path.Clean(pattern); parent != "/"; {
+				parent = path.Dir(parent)
+				if ok, _ := path.Match(parent, candidate); ok {
+					return pattern, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// aliasedPaths returns the clean path followed by the names it has under hostPathAliases
+func aliasedPaths(clean string) []string {
+	paths := []string{clean}
+	for _, alias := range hostPathAliases {
+		for i, dir := range alias {
+			other := alias[1-i]
+			if clean == dir {
+				paths = append(paths, other)
+			} else if rest, ok := strings.CutPrefix(clean, dir+"/"); ok {
+				paths = append(paths, other+"/"+rest)
+			}
+		}
+	}
+	return paths
+}
+
+// bindSources returns the host paths bind mounted by a container, including
+// the directories bound by volumes it defines with local bind options
+func bindSources(hostConfig *container.HostConfig) []string {
+	var sources []string
+	for _, bind := range hostConfig.Binds {
+		source, _, _ := strings.Cut(bind, ":")
+		if path.IsAbs(source) {
+			sources = append(sources, source)
+		}
+	}
+	for _, m := range hostConfig.Mounts {
+		switch {
+		case m.Type == mount.TypeBind:
+			sources = append(sources, m.Source)
+		case m.Type == mount.TypeVolume && m.VolumeOptions != nil && m.VolumeOptions.DriverConfig != nil:
+			driver := m.VolumeOptions.DriverConfig
+			if source, ok := localBindDevice(driver.Name, driver.Options); ok {
+				sources = append(sources, source)
+			}
+		}
+	}
+	return sources
+}
+
+// localBindDevice returns the host directory a local volume binds when its
+// options are those of a bind mount, such as {type: none, o: bind, device: /srv}
+func localBindDevice(driver string, options map[string]string) (string, bool) {
+	if driver != "" && driver != "local" {
+		return "", false
+	}
+	device := options["device"]
+	if !path.IsAbs(device) {
+		return "", false
+	}
+	for _, opt := range strings.Split(options["o"], ",") {
+		if opt == "bind" || opt == "rbind" {
+			return device, true
+		}
+	}
+	return "", false
+}
+
+// unconfined reports whether a security option turns off seccomp, AppArmor,
+// SELinux labeling or the masking of /proc and /sys paths
+func unconfined(opt string) bool {
+	key, value, ok := strings.Cut(opt, "=")
+	if !ok {
+		key, value, _ = strings.Cut(opt, ":")
+	}
+	switch key {
+	case "seccomp", "apparmor", "systempaths":
+		return value == "unconfined"
+	case "label":
+		return value == "disable"
+	}
+	return false
+}
+
+// normalizeCapability converts a capability name to the upper-case form without the CAP_ prefix
+func normalizeCapability(capability string) string {
+	return strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+}
+
+// violation returns a Violation for the reasons, or nil when there are none
+func violation(action string, reasons []string) error {
+	if len(reasons) == 0 {
+		return nil
+	}
+	return &Violation{Action: action, Reasons: reasons}
+}
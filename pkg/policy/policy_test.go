@@ -0,0 +1,384 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+)
+
+func TestHostPathDenied(t *testing.T) {
+	p := &Policy{DeniedHostPaths: []string{"/var/run/docker.sock", "/etc", "/home/*/.ssh"}}
+
+	tests := []struct {
+		source  string
+		pattern string // Empty when the path is allowed
+	}{
+		{"/var/run/docker.sock", "/var/run/docker.sock"},
+		{"/var/run/../run/docker.sock", "/var/run/docker.sock"},
+		{"/run/docker.sock", "/var/run/docker.sock"},
+		{"/run", "/var/run/docker.sock"},
+		{"/var/run", "/var/run/docker.sock"},
+		{"/var", "/var/run/docker.sock"},
+		{"/", "/var/run/docker.sock"},
+		{"/etc/passwd", "/etc"},
+		{"/etc/", "/etc"},
+		{"/home/alice/.ssh", "/home/*/.ssh"},
+		{"/home/alice/.ssh/id_rsa", "/home/*/.ssh"},
+		{"/home/alice", "/home/*/.ssh"},
+		{"/home", "/home/*/.ssh"},
+		{"/home/alice/src", ""},
+		{"/srv/data", ""},
+		{"/run/user/1000", ""},
+		{"/etcetera", ""},
+	}
+	for _, tt := range tests {
+		pattern, denied := p.hostPathDenied(tt.source)
+		if denied != (tt.pattern != "") || pattern != tt.pattern {
+			t.Errorf("hostPathDenied(%q) = %q, %v; want %q", tt.source, pattern, denied, tt.pattern)
+		}
+	}
+}
+
+func TestAliasedPaths(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/var/run/docker.sock", []string{"/var/run/docker.sock", "/run/docker.sock"}},
+		{"/run", []string{"/run", "/var/run"}},
+		{"/run/lock/x", []string{"/run/lock/x", "/var/run/lock/x", "/var/lock/x"}},
+		{"/runtime", []string{"/runtime"}},
+		{"/", []string{"/"}},
+	}
+	for _, tt := range tests {
+		if got := aliasedPaths(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("aliasedPaths(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestImageReasons(t *testing.T) {
+	p := &Policy{
+		AllowedRegistries:   []string{"ghcr.io"},
+		AllowedRepositories: []string{"docker.io/library/*", "quay.io/team/*"},
+	}
+
+	tests := []struct {
+		image   string
+		allowed bool
+	}{
+		{"busybox", true},
+		{"busybox:1.36", true},
+		{"docker.io/library/nginx@sha256:" + strings.Repeat("a", 64), true},
+		{"ghcr.io/any/thing:latest", true},
+		{"quay.io/team/app", true},
+		{"quay.io/team/nested/app", false},
+		{"evil/miner", false},
+		{"registry.example.com/library/busybox", false},
+		{"not a reference", false},
+	}
+	for _, tt := range tests {
+		reasons := p.imageReasons(tt.image)
+		if (len(reasons) == 0) != tt.allowed {
+			t.Errorf("imageReasons(%q) = %v, want allowed=%v", tt.image, reasons, tt.allowed)
+		}
+	}
+
+	if reasons := (&Policy{}).imageReasons("anything/at:all"); reasons != nil {
+		t.Errorf("unrestricted policy reported %v", reasons)
+	}
+}
+
+func TestCapabilityDenied(t *testing.T) {
+	tests := []struct {
+		denied     []string
+		capability string
+		want       bool
+	}{
+		{[]string{"SYS_ADMIN"}, "SYS_ADMIN", true},
+		{[]string{"SYS_ADMIN"}, "NET_ADMIN", false},
+		{[]string{"ALL"}, "CHOWN", true},
+		{[]string{"NET_RAW"}, "ALL", true},
+		{nil, "ALL", false},
+	}
+	for _, tt := range tests {
+		p := &Policy{DeniedCapabilities: tt.denied}
+		if got := p.capabilityDenied(tt.capability); got != tt.want {
+			t.Errorf("capabilityDenied(%v, %q) = %v, want %v", tt.denied, tt.capability, got, tt.want)
+		}
+	}
+}
+
+func TestUnconfined(t *testing.T) {
+	tests := map[string]bool{
+		"seccomp=unconfined":     true,
+		"seccomp:unconfined":     true,
+		"apparmor=unconfined":    true,
+		"systempaths=unconfined": true,
+		"label=disable":          true,
+		"label:disable":          true,
+		"seccomp=/profile.json":  false,
+		"apparmor=docker-custom": false,
+		"label=type:svirt_lxc":   false,
+		"no-new-privileges":      false,
+		"no-new-privileges=true": false,
+	}
+	for opt, want := range tests {
+		if got := unconfined(opt); got != want {
+			t.Errorf("unconfined(%q) = %v, want %v", opt, got, want)
+		}
+	}
+}
+
+func TestBindSources(t *testing.T) {
+	hostConfig := &container.HostConfig{
+		Binds: []string{"/srv:/data:ro", "cache:/cache", "/etc/ssl:/ssl"},
+		Mounts: []mount.Mount{
+			{Type: mount.TypeBind, Source: "/var/log", Target: "/logs"},
+			{Type: mount.TypeVolume, Source: "named", Target: "/named"},
+			{Type: mount.TypeVolume, Source: "hostroot", Target: "/host", VolumeOptions: &mount.VolumeOptions{
+				DriverConfig: &mount.Driver{Name: "local", Options: map[string]string{"type": "none", "o": "bind", "device": "/"}},
+			}},
+			{Type: mount.TypeVolume, Source: "nfs", Target: "/nfs", VolumeOptions: &mount.VolumeOptions{
+				DriverConfig: &mount.Driver{Name: "local", Options: map[string]string{"type": "nfs", "o": "addr=10.0.0.1", "device": ":/export"}},
+			}},
+			{Type: mount.TypeTmpfs, Target: "/tmp"},
+		},
+	}
+	want := []string{"/srv", "/etc/ssl", "/var/log", "/"}
+	if got := bindSources(hostConfig); !reflect.DeepEqual(got, want) {
+		t.Errorf("bindSources() = %v, want %v", got, want)
+	}
+}
+
+func TestLocalBindDevice(t *testing.T) {
+	tests := []struct {
+		driver  string
+		options map[string]string
+		device  string // Empty when the volume is not a bind mount
+	}{
+		{"", map[string]string{"type": "none", "o": "bind", "device": "/srv"}, "/srv"},
+		{"local", map[string]string{"o": "rbind,ro", "device": "/etc"}, "/etc"},
+		{"local", map[string]string{"o": "ro", "device": "/etc"}, ""},
+		{"local", map[string]string{"o": "bind", "device": "relative"}, ""},
+		{"nfs", map[string]string{"o": "bind", "device": "/srv"}, ""},
+		{"local", nil, ""},
+	}
+	for _, tt := range tests {
+		device, ok := localBindDevice(tt.driver, tt.options)
+		if ok != (tt.device != "") || device != tt.device {
+			t.Errorf("localBindDevice(%q, %v) = %q, %v; want %q", tt.driver, tt.options, device, ok, tt.device)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"policy.yaml":   "denied_host_paths: [/etc]\ndenied_capabilities: [cap_sys_admin]\nrequire_limits: {memory: true}\n",
+		"policy.json":   `{"denied_host_paths": ["/etc"], "denied_capabilities": ["CAP_SYS_ADMIN"], "require_limits": {"memory": true}}`,
+		"typo.yaml":     "deny_host_networking: true\n",
+		"relative.yaml": "denied_host_paths: [etc]\n",
+		"pattern.yaml":  "allowed_repositories: ['docker.io/[']\n",
+		"syntax.json":   `{"deny_host_network": true`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"policy.yaml", "policy.json"} {
+		p, err := Load(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		want := &Policy{
+			DeniedHostPaths:    []string{"/etc"},
+			DeniedCapabilities: []string{"SYS_ADMIN"},
+			RequireLimits:      ResourceLimits{Memory: true},
+		}
+		if !reflect.DeepEqual(p, want) {
+			t.Errorf("%s: got %+v, want %+v", name, p, want)
+		}
+	}
+
+	tests := map[string]string{
+		"typo.yaml":     "field deny_host_networking not found",
+		"relative.yaml": `denied host path "etc" must be absolute`,
+		"pattern.yaml":  "syntax error in pattern",
+		"syntax.json":   "failed to parse policy",
+		"missing.yaml":  "failed to open policy",
+	}
+	for name, want := range tests {
+		_, err := Load(filepath.Join(dir, name))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got error %v, want %q", name, err, want)
+		}
+	}
+}
+
+func TestCheckCreate(t *testing.T) {
+	p := &Policy{
+		DeniedHostPaths:    []string{"/var/run/docker.sock"},
+		DenyHostNetwork:    true,
+		DenyPrivileged:     true,
+		DeniedCapabilities: []string{"SYS_ADMIN"},
+		RequireLimits:      ResourceLimits{Memory: true, CPU: true, Pids: true},
+	}
+	limited := container.Resources{Memory: 64 << 20, NanoCPUs: 5e8, PidsLimit: new(int64)}
+	*limited.PidsLimit = 100
+
+	tests := []struct {
+		name       string
+		hostConfig container.HostConfig
+		joined     container.NetworkMode
+		reasons    []string
+	}{
+		{
+			name:       "allowed",
+			hostConfig: container.HostConfig{Resources: limited},
+		},
+		{
+			name: "every rule",
+			hostConfig: container.HostConfig{
+				NetworkMode: "host",
+				Privileged:  true,
+				CapAdd:      []string{"cap_sys_admin"},
+				SecurityOpt: []string{"seccomp=unconfined"},
+				Binds:       []string{"/run/docker.sock:/docker.sock"},
+			},
+			reasons: []string{
+				"host network mode is not allowed",
+				"privileged mode is not allowed",
+				"security option seccomp=unconfined is not allowed",
+				"capability SYS_ADMIN is not allowed",
+				"bind mount of /run/docker.sock is not allowed (denied host path /var/run/docker.sock)",
+				"a memory limit is required",
+				"a CPU limit is required",
+				"a pids limit is required",
+			},
+		},
+		{
+			name:       "joins a host network container",
+			hostConfig: container.HostConfig{NetworkMode: "container:web", Resources: limited},
+			joined:     "host",
+			reasons:    []string{"network mode container:web shares the host network"},
+		},
+		{
+			name:       "joins a bridged container",
+			hostConfig: container.HostConfig{NetworkMode: "container:web", Resources: limited},
+			joined:     "bridge",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.CheckCreate(&container.Config{Image: "busybox"}, &tt.hostConfig, tt.joined)
+			if tt.reasons == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			v, ok := err.(*Violation)
+			if !ok {
+				t.Fatalf("expected a violation, got %v", err)
+			}
+			if v.Action != "container creation" || !reflect.DeepEqual(v.Reasons, tt.reasons) {
+				t.Errorf("got %s: %q, want %q", v.Action, v.Reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	p := &Policy{RequireLimits: ResourceLimits{Memory: true, Pids: true}}
+
+	// A container created before the policy may keep lacking a limit
+	if err := p.CheckUpdate(container.Resources{}, container.Resources{CPUShares: 512}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := p.CheckUpdate(container.Resources{Memory: 64 << 20}, container.Resources{})
+	if err == nil || err.Error() != "container update denied by policy: the required memory limit cannot be removed" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckBuild(t *testing.T) {
+	p := &Policy{DenyHostNetwork: true, AllowedRepositories: []string{"docker.io/library/*"}}
+
+	tests := []struct {
+		name       string
+		options    types.ImageBuildOptions
+		dockerfile string
+		remote     bool
+		reason     string // Empty when the build is allowed
+	}{
+		{name: "allowed", dockerfile: "FROM busybox AS build\nFROM build\nFROM scratch\n"},
+		{name: "build arg", dockerfile: "ARG BASE=busybox\nFROM ${BASE}\n", options: types.ImageBuildOptions{BuildArgs: map[string]*string{"BASE": ptr("ghcr.io/evil/miner")}}, reason: "image ghcr.io/evil/miner is not from an allowed registry or repository"},
+		{name: "host network", dockerfile: "FROM busybox\n", options: types.ImageBuildOptions{NetworkMode: "host"}, reason: "host network mode is not allowed"},
+		{name: "remote", remote: true, reason: "base images of a remote build context cannot be verified"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dockerfile []byte
+			if !tt.remote {
+				dockerfile = []byte(tt.dockerfile)
+			}
+			err := p.CheckBuild(tt.options, dockerfile)
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.reason) {
+				t.Fatalf("got error %v, want %q", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestCheckVolume(t *testing.T) {
+	p := &Policy{DeniedHostPaths: []string{"/etc"}}
+
+	if err := p.CheckVolume(volume.CreateOptions{DriverOpts: map[string]string{"o": "bind", "device": "/srv"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := p.CheckVolume(volume.CreateOptions{Driver: "local", DriverOpts: map[string]string{"o": "bind", "device": "/etc/ssl"}})
+	if err == nil || !strings.Contains(err.Error(), "bind mount of /etc/ssl is not allowed (denied host path /etc)") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNilPolicy(t *testing.T) {
+	var p *Policy
+	hostConfig := &container.HostConfig{NetworkMode: "host", Privileged: true}
+	if err := p.CheckCreate(&container.Config{Image: "anything"}, hostConfig, "host"); err != nil {
+		t.Errorf("CheckCreate: %v", err)
+	}
+	if err := p.CheckUpdate(container.Resources{Memory: 1}, container.Resources{}); err != nil {
+		t.Errorf("CheckUpdate: %v", err)
+	}
+	if err := p.CheckExec(types.ContainerJSON{}, true); err != nil {
+		t.Errorf("CheckExec: %v", err)
+	}
+	if err := p.CheckVolume(volume.CreateOptions{DriverOpts: map[string]string{"o": "bind", "device": "/"}}); err != nil {
+		t.Errorf("CheckVolume: %v", err)
+	}
+	if err := p.CheckBuild(types.ImageBuildOptions{NetworkMode: "host"}, nil); err != nil {
+		t.Errorf("CheckBuild: %v", err)
+	}
+}
+
+// ptr returns a pointer to s
+func ptr(s string) *string {
+	return &s
+}
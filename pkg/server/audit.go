@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/coolbit-in/docker-mcp/pkg/audit"
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/coolbit-in/docker-mcp/pkg/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// targetArguments are the tool arguments that name the objects a call acts on
+var targetArguments = []string{"container_id", "container_ids", "image", "image_name", "network_id", "name", "new_name", "tag", "tags"}
+
+// auditMiddleware wraps a tool handler so every invocation is written to the audit log,
+// with secrets in its arguments masked by redactor.
+// A failure to write the record is logged but does not fail the call.
+func auditMiddleware(logger *audit.Logger, redactor *redact.Redactor, tool string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, request)
+
+		record := audit.Record{
+			Time:       start,
+			Tool:       tool,
+			Arguments:  audit.Sanitize(request.Params.Arguments, redactor),
+			DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if principal, ok := auth.FromContext(ctx); ok {
+			record.Principal = principal
+		}
+
+		response, decoded := toolResponse(result)
+		switch {
+		case err != nil:
+			record.Error = err.Error()
+		case decoded:
+			record.Success = response.Success
+			record.Error = response.Error
+		default:
+			record.Success = result != nil && !result.IsError
+		}
+		record.Targets = targets(request.Params.Arguments, response.Data)
+
+		if logErr := logger.Log(record); logErr != nil {
+			slog.Warn("Failed to write audit record", "tool", tool, "error", logErr)
+		}
+		return result, err
+	}
+}
+
+// toolResponse decodes the standard API response from a tool result
+func toolResponse(result *mcp.CallToolResult) (models.APIResponse, bool) {
+	var response models.APIResponse
+	if result == nil || len(result.Content) == 0 {
+		return response, false
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return response, false
+	}
+	return response, json.Unmarshal([]byte(text.Text), &response) == nil
+}
+
+// targets collects the IDs and names of the objects a call acted on, from
+// its arguments and the IDs of objects it created
+func targets(args map[string]interface{}, data json.RawMessage) []string {
+	var result []string
+	seen := make(map[string]bool)
+	add := func(value string) {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+
+	for _, key := range targetArguments {
+		switch v := args[key].(type) {
+		case string:
+			add(v)
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					add(s)
+				}
+			}
+		}
+	}
+
+	// Responses describing a single object carry its ID
+	var created struct {
+		ID      string `json:"id"`
+		ImageID string `json:"image_id"`
+	}
+	if json.Unmarshal(data, &created) == nil {
+		add(created.ID)
+		add(created.ImageID)
+	}
+
+	return result
+}
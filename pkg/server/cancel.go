@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// methodCancelled is the notification a client sends to abandon an in-flight request
+const methodCancelled = "notifications/cancelled"
+
+// requestCanceller tracks in-flight requests so that notifications/cancelled
+// can cancel their contexts. It only helps transports that process each
+// message in its own goroutine (SSE and streamable HTTP); the stdio transport
+// reads the next message only after the current one completes.
+type requestCanceller struct {
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc // session ID + request ID -> cancel
+}
+
+// newRequestCanceller creates an empty canceller
+func newRequestCanceller() *requestCanceller {
+	return &requestCanceller{inflight: make(map[string]context.CancelFunc)}
+}
+
+// rpcEnvelope holds the fields used to route a JSON-RPC message
+type rpcEnvelope struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params struct {
+		RequestID json.RawMessage `json:"requestId,omitempty"`
+	} `json:"params"`
+}
+
+// track derives a cancellable context for a request message. Cancellation
+// notifications are applied immediately. The returned function must be
+// called once the message has been handled.
+func (c *requestCanceller) track(ctx context.Context, sessionID string, message json.RawMessage) (context.Context, func()) {
+	var env rpcEnvelope
+	if err := json.Unmarshal(message, &env); err != nil {
+		return ctx, func() {}
+	}
+
+	if env.Method == methodCancelled {
+		c.cancel(sessionID, env.Params.RequestID)
+		return ctx, func() {}
+	}
+	if len(env.ID) == 0 || env.Method == "" {
+		return ctx, func() {}
+	}
+
+	key := requestKey(sessionID, env.ID)
+	ctx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.inflight[key] = cancel
+	c.mu.Unlock()
+
+	return ctx, func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels the in-flight request with the given ID, if any
+func (c *requestCanceller) cancel(sessionID string, requestID json.RawMessage) {
+	if len(requestID) == 0 {
+		return
+	}
+	c.mu.Lock()
+	cancel, ok := c.inflight[requestKey(sessionID, requestID)]
+	c.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// middleware applies cancellation to messages POSTed to next; sessionID extracts the session from the request
+func (c *requestCanceller) middleware(next http.Handler, sessionID func(r *http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+		if err != nil {
+			writeJSONRPCError(w, http.StatusRequestEntityTooLarge, mcp.INVALID_REQUEST, "Request body too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx, done := c.track(r.Context(), sessionID(r), body)
+		defer done()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestKey builds the inflight map key for a request
+func requestKey(sessionID string, requestID json.RawMessage) string {
+	return sessionID + "\x00" + string(bytes.TrimSpace(requestID))
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerNetworkTools registers the network management tools with the MCP server
+func (s *DockerMCPServer) registerNetworkTools() {
+	// List networks tool
+	s.addTool(
+		mcp.NewTool("list_networks",
+			mcp.WithDescription("List Docker networks with their IDs, names, drivers, scopes and subnets. Returns array of network objects."),
+			mcp.WithString("name",
+				mcp.Description("Only show networks whose name contains this value"),
+			),
+			mcp.WithString("driver",
+				mcp.Description("Only show networks using this driver (e.g. bridge, overlay)"),
+			),
+		),
+		s.handler.HandleListNetworks,
+	)
+
+	// Inspect network tool
+	s.addTool(
+		mcp.NewTool("inspect_network",
+			mcp.WithDescription("Return detailed information about a network, including connected containers."),
+			mcp.WithString("network_id",
+				mcp.Description("Network ID or name to inspect"),
+				mcp.Required(),
+			),
+		),
+		s.handler.HandleInspectNetwork,
+	)
+
+	// Create network tool
+	s.addTool(
+		mcp.NewTool("create_network",
+			mcp.WithDescription("Create a new Docker network."),
+			mcp.WithString("name",
+				mcp.Description("Network name"),
+				mcp.Required(),
+			),
+			mcp.WithString("driver",
+				mcp.Description("Network driver (bridge, overlay, macvlan, ...)"),
+				mcp.DefaultString("bridge"),
+			),
+			mcp.WithBoolean("internal",
+				mcp.Description("Restrict external access to the network"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("attachable",
+				mcp.Description("Allow manual container attachment (swarm networks)"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("enable_ipv6",
+				mcp.Description("Enable IPv6 networking"),
+			),
+			mcp.WithString("subnet",
+				mcp.Description("Subnet in CIDR format (e.g. 172.28.0.0/16)"),
+			),
+			mcp.WithString("gateway",
+				mcp.Description("Gateway for the subnet (requires subnet)"),
+			),
+			mcp.WithString("ip_range",
+				mcp.Description("Allocate container IPs from a sub-range (requires subnet)"),
+			),
+			mcp.WithObject("options",
+				mcp.Description("Driver specific options (format: {\"key\": \"value\"})"),
+			),
+			mcp.WithObject("labels",
+				mcp.Description("Network labels (format: {\"key\": \"value\"})"),
+			),
+		),
+		s.handler.HandleCreateNetwork,
+	)
+
+	// Remove network tool
+	s.addTool(
+		mcp.NewTool("remove_network",
+			mcp.WithDescription("Remove a network."),
+			mcp.WithString("network_id",
+				mcp.Description("Network ID or name to remove"),
+				mcp.Required(),
+			),
+		),
+		s.handler.HandleRemoveNetwork,
+	)
+
+	// Prune networks tool
+	s.addTool(
+		mcp.NewTool("prune_networks",
+			mcp.WithDescription("Remove all networks not used by at least one container. Returns the removed network names."),
+			mcp.WithString("until",
+				mcp.Description("Only remove networks created before this timestamp or duration (e.g. 24h)"),
+			),
+			mcp.WithArray("labels",
+				mcp.Description("Only remove networks with these labels (format: key or key=value)"),
+			),
+		),
+		s.handler.HandlePruneNetworks,
+	)
+
+	// Connect container to network tool
+	s.addTool(
+		mcp.NewTool("connect_network",
+			mcp.WithDescription("Connect a container to a network."),
+			mcp.WithString("network_id",
+				mcp.Description("Network ID or name"),
+				mcp.Required(),
+			),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name to connect"),
+				mcp.Required(),
+			),
+			mcp.WithArray("aliases",
+				mcp.Description("Network-scoped DNS aliases for the container"),
+			),
+			mcp.WithString("ipv4_address",
+				mcp.Description("Static IPv4 address for the container on this network"),
+			),
+			mcp.WithString("ipv6_address",
+				mcp.Description("Static IPv6 address for the container on this network"),
+			),
+			mcp.WithObject("driver_opts",
+				mcp.Description("Endpoint driver options (format: {\"key\": \"value\"})"),
+			),
+		),
+		s.handler.HandleConnectNetwork,
+	)
+
+	// Disconnect container from network tool
+	s.addTool(
+		mcp.NewTool("disconnect_network",
+			mcp.WithDescription("Disconnect a container from a network."),
+			mcp.WithString("network_id",
+				mcp.Description("Network ID or name"),
+				mcp.Required(),
+			),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name to disconnect"),
+				mcp.Required(),
+			),
+			mcp.WithBoolean("force",
+				mcp.Description("Force the container to disconnect"),
+				mcp.DefaultBool(false),
+			),
+		),
+		s.handler.HandleDisconnectNetwork,
+	)
+}
@@ -1,10 +1,18 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/coolbit-in/docker-mcp/pkg/audit"
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+	"github.com/coolbit-in/docker-mcp/pkg/docker"
 	"github.com/coolbit-in/docker-mcp/pkg/handlers"
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+	"github.com/coolbit-in/docker-mcp/pkg/redact"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -13,33 +21,74 @@ import (
 type DockerMCPServer struct {
 	mcpServer *server.MCPServer
 	handler   *handlers.Handler
+	tools     ToolFilter
+	audit     *audit.Logger
+	redactor  *redact.Redactor // Masks secrets in audited arguments
+	dryRun    bool             // Refuse mutating tools that cannot be planned
+	defined   map[string]bool  // Names of every defined tool, registered or not
+}
+
+// Options configures a Docker MCP server. The zero value uses the defaults.
+type Options struct {
+	Handlers handlers.Options // Limits applied by the tool handlers
+	Tools    ToolFilter       // Tools exposed to clients
+	Audit    *audit.Logger    // Records every tool invocation; nil disables auditing
 }
 
 // NewDockerMCPServer creates a new Docker MCP server instance
-func NewDockerMCPServer(socketPath string) (*DockerMCPServer, error) {
-	handler, err := handlers.NewHandler(socketPath)
+func NewDockerMCPServer(socketPath string, opts Options) (*DockerMCPServer, error) {
+	handler, err := handlers.NewHandler(socketPath, opts.Handlers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create handler: %w", err)
 	}
 
+	s, err := newDockerMCPServer(handler, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Docker MCP server created successfully", "socket", socketPath)
+	return s, nil
+}
+
+// NewDockerMCPServerWithClient creates a Docker MCP server that uses the given Docker API implementation
+func NewDockerMCPServerWithClient(client docker.API, opts Options) (*DockerMCPServer, error) {
+	return newDockerMCPServer(handlers.NewHandlerWithClient(client, opts.Handlers), opts)
+}
+
+// newDockerMCPServer creates the MCP server and registers the tools selected by opts, backed by handler
+func newDockerMCPServer(handler *handlers.Handler, opts Options) (*DockerMCPServer, error) {
 	// 创建 MCP 服务器
 	srv := server.NewMCPServer(
 		"docker-mcp",
 		"1.0.0",
 		server.WithToolCapabilities(true),
+		server.WithLogging(),
 	)
 
+	// Audited arguments are masked like tool output, which defaults to redact.Default()
+	if opts.Handlers.Redactor == nil {
+		opts.Handlers.Redactor = redact.Default()
+	}
+
 	s := &DockerMCPServer{
 		mcpServer: srv,
 		handler:   handler,
+		tools:     opts.Tools,
+		audit:     opts.Audit,
+		redactor:  opts.Handlers.Redactor,
+		dryRun:    opts.Handlers.DryRun,
+		defined:   make(map[string]bool),
 	}
 
 	// 注册所有工具
 	if err := s.registerTools(); err != nil {
 		return nil, err
 	}
+	if err := s.tools.validate(s.defined); err != nil {
+		return nil, err
+	}
 
-	slog.Info("Docker MCP server created successfully", "socket", socketPath)
 	return s, nil
 }
 
@@ -48,7 +97,7 @@ func (s *DockerMCPServer) registerTools() error {
 	slog.Debug("Registering Docker MCP tools")
 
 	// List containers tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("list_containers",
 			mcp.WithDescription("List all running Docker containers with their IDs, names, images, and status. Returns array of container objects."),
 			mcp.WithBoolean("all",
@@ -60,25 +109,50 @@ func (s *DockerMCPServer) registerTools() error {
 	)
 
 	// Execute command in container tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("exec_command",
-			mcp.WithDescription("Execute a shell command in a specified container. Requires container_id and command parameters. Returns command output."),
+			mcp.WithDescription("Execute a command in a specified container, either as a shell command string or as an argv array run without a shell. Returns stdout, stderr and the exit code; a non-zero exit_code means the command failed."),
 			mcp.WithString("container_id",
 				mcp.Description("Container ID (string)"),
 				mcp.Required(),
 			),
 			mcp.WithString("command",
-				mcp.Description("Command to execute (string)"),
-				mcp.Required(),
+				mcp.Description("Shell command to execute with sh -c (string). Either command or argv is required"),
+			),
+			mcp.WithArray("argv",
+				mcp.Description("Command and arguments executed directly without a shell, for images that have none (e.g. [\"/app\", \"--version\"])"),
+			),
+			mcp.WithString("user",
+				mcp.Description("User to run the command as (user, user:group, uid or uid:gid)"),
+			),
+			mcp.WithArray("env",
+				mcp.Description("Additional environment variables (format: KEY=VALUE)"),
+			),
+			mcp.WithString("working_dir",
+				mcp.Description("Working directory for the command"),
+			),
+			mcp.WithBoolean("privileged",
+				mcp.Description("Run the command with extended privileges"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithString("stdin",
+				mcp.Description("Content to pipe to the command's standard input"),
+			),
+			mcp.WithNumber("timeout",
+				mcp.Description("Seconds to wait for the command before killing it and returning the partial output with timed_out set. Killing needs sh, tr and grep in the container; killed reports whether it worked"),
+			),
+			mcp.WithBoolean("redact",
+				mcp.Description("Mask secrets such as password and token variables and credential formats in the output. Set false to return the output unmasked"),
+				mcp.DefaultBool(true),
 			),
 		),
 		s.handler.HandleExecCommand,
 	)
 
 	// Pull image tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("pull_image",
-			mcp.WithDescription("Pull Docker image from registry. Requires image_name parameter (format: name:tag). Returns streaming progress updates."),
+			mcp.WithDescription("Pull Docker image from registry. Requires image_name parameter (format: name:tag). Sends MCP progress notifications (overall percentage across layers) when the call includes a progressToken, and returns the image digest."),
 			mcp.WithString("image_name",
 				mcp.Description("Image name with tag (string)"),
 				mcp.Required(),
@@ -88,7 +162,7 @@ func (s *DockerMCPServer) registerTools() error {
 	)
 
 	// List images tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("list_images",
 			mcp.WithDescription("List all locally stored Docker images. Returns array of image objects with ID, tags, size and creation time."),
 			mcp.WithBoolean("all",
@@ -100,7 +174,7 @@ func (s *DockerMCPServer) registerTools() error {
 	)
 
 	// Search Docker Hub tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("search",
 			mcp.WithDescription("Search for Docker images on Docker Hub. Returns array of image results including name, description, official status, and star count."),
 			mcp.WithString("term",
@@ -118,7 +192,7 @@ func (s *DockerMCPServer) registerTools() error {
 	)
 
 	// Create container tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("create_container",
 			mcp.WithDescription("Create a new Docker container from an image. Requires image name and container configuration."),
 			mcp.WithString("image",
@@ -136,10 +210,13 @@ func (s *DockerMCPServer) registerTools() error {
 				mcp.Description("Environment variables (format: KEY=VALUE)"),
 			),
 			mcp.WithObject("ports",
-				mcp.Description("Port mappings (format: {\"host_port:container_port/protocol\": {}}"),
+				mcp.Description("Port mappings. Keys are [host_ip:]host_port:container_port[/protocol] (format: {\"8080:80/tcp\": {}}), or a container port whose value is the host port ({\"80\": \"8080\"}). The protocol defaults to tcp; an empty host port lets the daemon pick one."),
 			),
 			mcp.WithArray("volumes",
-				mcp.Description("Volume mappings (format: host_path:container_path)"),
+				mcp.Description("Volume mappings passed to Docker as binds (format: source:container_path[:options]). Absolute sources are host paths, which Docker creates when missing, other sources are named volumes; options include ro, rw, z, Z, nocopy and bind propagation modes."),
+			),
+			mcp.WithArray("mounts",
+				mcp.Description("Structured mounts. Each element is {\"type\": \"bind|volume|tmpfs\", \"source\", \"target\", \"read_only\"} plus \"propagation\" for bind, \"no_copy\", \"volume_driver\", \"volume_driver_opts\", \"volume_labels\" for volume, and \"tmpfs_size\" (bytes), \"tmpfs_mode\" (octal string) for tmpfs."),
 			),
 			mcp.WithString("working_dir",
 				mcp.Description("Working directory inside container"),
@@ -147,31 +224,133 @@ func (s *DockerMCPServer) registerTools() error {
 			mcp.WithString("network_mode",
 				mcp.Description("Network mode (bridge, host, none, container:<name|id>)"),
 			),
+			mcp.WithArray("networks",
+				mcp.Description("Networks to attach at creation. Each element is a network name or {\"name\", \"aliases\", \"ipv4_address\", \"ipv6_address\", \"driver_opts\"}. The first network is the primary one unless network_mode is set."),
+			),
 			mcp.WithString("restart_policy",
 				mcp.Description("Restart policy (no, always, on-failure, unless-stopped)"),
 			),
+			mcp.WithNumber("restart_max_retries",
+				mcp.Description("Maximum restart attempts for the on-failure restart policy (default 3, 0 for no limit)"),
+			),
 			mcp.WithBoolean("auto_remove",
 				mcp.Description("Automatically remove container when it exits"),
 				mcp.DefaultBool(false),
 			),
+			mcp.WithString("memory",
+				mcp.Description("Memory limit (e.g. 512m, 2g)"),
+			),
+			mcp.WithNumber("cpus",
+				mcp.Description("Number of CPUs the container may use (e.g. 0.5)"),
+			),
+			mcp.WithNumber("pids_limit",
+				mcp.Description("Maximum number of processes in the container"),
+			),
+			mcp.WithNumber("cpu_shares",
+				mcp.Description("CPU shares, the container's weight relative to others (default 1024)"),
+			),
+			mcp.WithNumber("cpu_period",
+				mcp.Description("CPU CFS period in microseconds"),
+			),
+			mcp.WithNumber("cpu_quota",
+				mcp.Description("CPU CFS quota in microseconds per period; exclusive with cpus"),
+			),
+			mcp.WithString("cpuset_cpus",
+				mcp.Description("CPUs the container may run on (e.g. 0-3 or 0,2)"),
+			),
+			mcp.WithString("cpuset_mems",
+				mcp.Description("Memory nodes the container may use (e.g. 0-1)"),
+			),
+			mcp.WithString("memory_reservation",
+				mcp.Description("Memory soft limit (e.g. 256m)"),
+			),
+			mcp.WithString("memory_swap",
+				mcp.Description("Total memory plus swap limit (e.g. 1g), or -1 (as a number or a string) for unlimited swap"),
+			),
+			mcp.WithNumber("blkio_weight",
+				mcp.Description("Block I/O weight relative to other containers (10 to 1000)"),
+			),
+			mcp.WithArray("entrypoint",
+				mcp.Description("Entrypoint overriding the image's; an empty array clears it"),
+			),
+			mcp.WithString("user",
+				mcp.Description("User to run as (name, uid, name:group or uid:gid)"),
+			),
+			mcp.WithString("hostname",
+				mcp.Description("Container host name"),
+			),
+			mcp.WithObject("labels",
+				mcp.Description("Container labels (format: {\"key\": \"value\"})"),
+			),
+			mcp.WithObject("healthcheck",
+				mcp.Description("Healthcheck as {\"test\", \"interval\", \"timeout\", \"start_period\", \"start_interval\", \"retries\"}. test is a shell command string or an argument array, optionally starting with CMD or CMD-SHELL; durations are strings such as 30s. {\"disable\": true} turns off the image's healthcheck."),
+			),
+			mcp.WithArray("cap_add",
+				mcp.Description("Linux capabilities to add (e.g. NET_ADMIN)"),
+			),
+			mcp.WithArray("cap_drop",
+				mcp.Description("Linux capabilities to drop (e.g. ALL)"),
+			),
+			mcp.WithBoolean("read_only",
+				mcp.Description("Mount the container's root filesystem read-only"),
+			),
+			mcp.WithArray("tmpfs",
+				mcp.Description("tmpfs mounts (format: path[:options], e.g. /run:size=64m,mode=1777)"),
+			),
+			mcp.WithObject("ulimits",
+				mcp.Description("Resource limits by name, each a number or a soft:hard string (format: {\"nofile\": \"1024:2048\", \"nproc\": 512})"),
+			),
+			mcp.WithArray("extra_hosts",
+				mcp.Description("Additional /etc/hosts entries (format: host:ip, or host:host-gateway)"),
+			),
+			mcp.WithArray("dns",
+				mcp.Description("DNS server IP addresses"),
+			),
+			mcp.WithArray("dns_search",
+				mcp.Description("DNS search domains"),
+			),
+			mcp.WithArray("dns_options",
+				mcp.Description("DNS resolver options (e.g. ndots:2)"),
+			),
+			mcp.WithArray("security_opt",
+				mcp.Description("Security options: no-new-privileges[:true|false], label=..., apparmor=..., seccomp=... or systempaths=unconfined"),
+			),
+			mcp.WithString("stop_signal",
+				mcp.Description("Signal sent to stop the container (e.g. SIGTERM)"),
+			),
+			mcp.WithNumber("stop_timeout",
+				mcp.Description("Seconds to wait after the stop signal before killing the container"),
+			),
+			mcp.WithString("log_driver",
+				mcp.Description("Logging driver (e.g. json-file, local, none)"),
+			),
+			mcp.WithObject("log_opts",
+				mcp.Description("Logging driver options (format: {\"max-size\": \"10m\"})"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
 		),
 		s.handler.HandleCreateContainer,
 	)
 
 	// Start container tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("start_container",
 			mcp.WithDescription("Start one or more stopped containers."),
 			mcp.WithString("container_id",
 				mcp.Description("Container ID or name to start"),
 				mcp.Required(),
 			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
 		),
 		s.handler.HandleStartContainer,
 	)
 
 	// Stop container tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("stop_container",
 			mcp.WithDescription("Stop a running container."),
 			mcp.WithString("container_id",
@@ -182,12 +361,15 @@ func (s *DockerMCPServer) registerTools() error {
 				mcp.Description("Seconds to wait before killing the container"),
 				mcp.DefaultNumber(10),
 			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
 		),
 		s.handler.HandleStopContainer,
 	)
 
 	// Restart container tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("restart_container",
 			mcp.WithDescription("Restart a container."),
 			mcp.WithString("container_id",
@@ -198,12 +380,15 @@ func (s *DockerMCPServer) registerTools() error {
 				mcp.Description("Seconds to wait before killing the container"),
 				mcp.DefaultNumber(10),
 			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
 		),
 		s.handler.HandleRestartContainer,
 	)
 
 	// Remove container tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("remove_container",
 			mcp.WithDescription("Remove one or more containers."),
 			mcp.WithString("container_id",
@@ -218,12 +403,156 @@ func (s *DockerMCPServer) registerTools() error {
 				mcp.Description("Remove anonymous volumes associated with the container"),
 				mcp.DefaultBool(false),
 			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
 		),
 		s.handler.HandleRemoveContainer,
 	)
 
+	// Update container tool
+	s.addTool(
+		mcp.NewTool("update_container",
+			mcp.WithDescription("Change the resource limits and restart policy of a container in place, without recreating it. Returns each requested setting with its value before and after."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name to update"),
+				mcp.Required(),
+			),
+			mcp.WithNumber("cpu_shares",
+				mcp.Description("CPU shares, the container's weight relative to others (default 1024)"),
+			),
+			mcp.WithNumber("cpus",
+				mcp.Description("Number of CPUs the container may use (e.g. 1.5); exclusive with cpu_period and cpu_quota"),
+			),
+			mcp.WithNumber("cpu_period",
+				mcp.Description("CPU CFS period in microseconds"),
+			),
+			mcp.WithNumber("cpu_quota",
+				mcp.Description("CPU CFS quota in microseconds per period, or -1 to remove the quota"),
+			),
+			mcp.WithString("cpuset_cpus",
+				mcp.Description("CPUs the container may run on (e.g. 0-3 or 0,2)"),
+			),
+			mcp.WithString("cpuset_mems",
+				mcp.Description("Memory nodes the container may use (e.g. 0-1)"),
+			),
+			mcp.WithString("memory",
+				mcp.Description("Memory limit (e.g. 512m, 2g)"),
+			),
+			mcp.WithString("memory_reservation",
+				mcp.Description("Memory soft limit (e.g. 256m)"),
+			),
+			mcp.WithString("memory_swap",
+				mcp.Description("Total memory plus swap limit (e.g. 1g), or -1 (as a number or a string) for unlimited swap"),
+			),
+			mcp.WithNumber("pids_limit",
+				mcp.Description("Maximum number of processes, or 0 to remove the limit"),
+			),
+			mcp.WithNumber("blkio_weight",
+				mcp.Description("Block I/O weight relative to other containers (10 to 1000)"),
+			),
+			mcp.WithString("restart_policy",
+				mcp.Description("Restart policy (no, always, on-failure, unless-stopped)"),
+			),
+			mcp.WithNumber("restart_max_retries",
+				mcp.Description("Maximum restart attempts for the on-failure restart policy (default 3, 0 for no limit)"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
+		),
+		s.handler.HandleUpdateContainer,
+	)
+
+	// Pause container tool
+	s.addTool(
+		mcp.NewTool("pause_container",
+			mcp.WithDescription("Suspend all processes in a running container."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name to pause"),
+				mcp.Required(),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
+		),
+		s.handler.HandlePauseContainer,
+	)
+
+	// Unpause container tool
+	s.addTool(
+		mcp.NewTool("unpause_container",
+			mcp.WithDescription("Resume the processes of a paused container."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name to unpause"),
+				mcp.Required(),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
+		),
+		s.handler.HandleUnpauseContainer,
+	)
+
+	// Kill container tool
+	s.addTool(
+		mcp.NewTool("kill_container",
+			mcp.WithDescription("Send a signal to the main process of a running container, e.g. SIGHUP to reload its configuration. Unlike stop_container, no other signal follows."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name"),
+				mcp.Required(),
+			),
+			mcp.WithString("signal",
+				mcp.Description("Signal name or number, e.g. SIGHUP, HUP or 1 (default SIGKILL)"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
+		),
+		s.handler.HandleKillContainer,
+	)
+
+	// Rename container tool
+	s.addTool(
+		mcp.NewTool("rename_container",
+			mcp.WithDescription("Give a container a new name."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name to rename"),
+				mcp.Required(),
+			),
+			mcp.WithString("new_name",
+				mcp.Description("New container name"),
+				mcp.Required(),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
+		),
+		s.handler.HandleRenameContainer,
+	)
+
+	// Wait container tool
+	s.addTool(
+		mcp.NewTool("wait_container",
+			mcp.WithDescription("Block until a container stops or is removed, then return its exit code and any error reported by the daemon. Returns timed_out if the condition is not met within the timeout."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name to wait for"),
+				mcp.Required(),
+			),
+			mcp.WithString("condition",
+				mcp.Description("Condition to wait for: not-running (returns at once for a stopped container), next-exit or removed"),
+				mcp.Enum("not-running", "next-exit", "removed"),
+				mcp.DefaultString("not-running"),
+			),
+			mcp.WithNumber("timeout",
+				mcp.Description("Seconds to wait before giving up (default 60, max 600)"),
+			),
+		),
+		s.handler.HandleWaitContainer,
+	)
+
 	// Remove image tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("remove_image",
 			mcp.WithDescription("Remove one or more images."),
 			mcp.WithString("image",
@@ -234,73 +563,197 @@ func (s *DockerMCPServer) registerTools() error {
 				mcp.Description("Force remove image"),
 				mcp.DefaultBool(false),
 			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
 		),
 		s.handler.HandleRemoveImage,
 	)
 
 	// Container logs tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("logs",
-			mcp.WithDescription("Get logs from a container."),
+			mcp.WithDescription("Get logs from a container, split into stdout and stderr (or as lines tagged with their stream). Lines can be filtered by time window and regular expressions; the most recent lines within max_lines/max_bytes are returned, with a truncation marker when earlier lines were dropped."),
 			mcp.WithString("container_id",
 				mcp.Description("Container ID or name to get logs from"),
 				mcp.Required(),
 			),
 			mcp.WithBoolean("follow",
-				mcp.Description("Follow log output"),
+				mcp.Description("Follow log output, streaming each line to the client as a log (and, when a progress token is given, progress) notification until follow_duration or follow_lines is reached; the result summarizes the session. Only new lines are streamed unless tail is set. Over the stdio transport the call cannot be cancelled and runs until follow_duration or follow_lines is reached"),
 				mcp.DefaultBool(false),
 			),
+			mcp.WithNumber("follow_duration",
+				mcp.Description("Seconds to follow the logs (default 30, maximum 600)"),
+				mcp.DefaultNumber(30),
+			),
+			mcp.WithNumber("follow_lines",
+				mcp.Description("Stop following after this many lines have been streamed (0 means no limit)"),
+			),
 			mcp.WithBoolean("timestamps",
 				mcp.Description("Show timestamps"),
 				mcp.DefaultBool(false),
 			),
 			mcp.WithString("tail",
-				mcp.Description("Number of lines to show from the end of the logs"),
-				mcp.DefaultString("all"),
+				mcp.Description("Number of lines to show from the end of the logs, or \"all\" (default \"all\", or \"0\" when following)"),
+			),
+			mcp.WithString("since",
+				mcp.Description("Show logs since a timestamp (RFC 3339 or Unix) or relative duration (e.g. 10m)"),
+			),
+			mcp.WithString("until",
+				mcp.Description("Show logs before a timestamp (RFC 3339 or Unix) or relative duration (e.g. 10m)"),
+			),
+			mcp.WithString("include",
+				mcp.Description("Only return lines matching this regular expression"),
+			),
+			mcp.WithString("exclude",
+				mcp.Description("Drop lines matching this regular expression"),
+			),
+			mcp.WithString("format",
+				mcp.Description("Output format: split (separate stdout and stderr) or tagged (ordered lines tagged with their stream)"),
+				mcp.Enum("split", "tagged"),
+				mcp.DefaultString("split"),
+			),
+			mcp.WithNumber("max_lines",
+				mcp.Description("Maximum number of lines to return, keeping the most recent (0 means no limit)"),
+			),
+			mcp.WithNumber("max_bytes",
+				mcp.Description("Maximum bytes of log text to return, keeping the most recent lines; a longer last line is cut with a marker (0 means no limit)"),
+				mcp.DefaultNumber(65536),
+			),
+			mcp.WithBoolean("redact",
+				mcp.Description("Mask secrets such as password and token variables and credential formats in the output. Set false to return the output unmasked"),
+				mcp.DefaultBool(true),
 			),
 		),
 		s.handler.HandleContainerLogs,
 	)
 
 	// Inspect container tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("inspect_container",
 			mcp.WithDescription("Return detailed information about a container."),
 			mcp.WithString("container_id",
 				mcp.Description("Container ID or name to inspect"),
 				mcp.Required(),
 			),
+			mcp.WithBoolean("redact",
+				mcp.Description("Mask secrets such as password and token variables and credential formats in the output. Set false to return the output unmasked"),
+				mcp.DefaultBool(true),
+			),
 		),
 		s.handler.HandleInspectContainer,
 	)
 
+	// Container stats tool
+	s.addTool(
+		mcp.NewTool("container_stats",
+			mcp.WithDescription("Sample live resource usage of containers: CPU percent, memory usage and limit, network and block I/O totals and rates, and process count. Each container is sampled twice, a window apart. Without container_id or container_ids, every running container is sampled."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name to sample"),
+			),
+			mcp.WithArray("container_ids",
+				mcp.Description("Container IDs or names to sample"),
+			),
+			mcp.WithNumber("window",
+				mcp.Description("Seconds between the two samples used for CPU usage and rates (default 1, max 10)"),
+			),
+		),
+		s.handler.HandleContainerStats,
+	)
+
+	// Container processes tool
+	s.addTool(
+		mcp.NewTool("container_top",
+			mcp.WithDescription("List the processes running in a container, as reported by ps on the host, so images need no ps of their own. Returns the column titles and one row per process keyed by title."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name"),
+				mcp.Required(),
+			),
+			mcp.WithString("ps_args",
+				mcp.Description("Arguments passed to ps (default \"-ef\"), e.g. \"aux\" or \"-eo pid,user,rss,args\""),
+			),
+			mcp.WithBoolean("redact",
+				mcp.Description("Mask secrets such as password and token variables and credential formats in the output. Set false to return the output unmasked"),
+				mcp.DefaultBool(true),
+			),
+		),
+		s.handler.HandleContainerTop,
+	)
+
+	// Container filesystem changes tool
+	s.addTool(
+		mcp.NewTool("container_diff",
+			mcp.WithDescription("List the files and directories added, changed or deleted in a container's filesystem since it was created from its image."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name"),
+				mcp.Required(),
+			),
+			mcp.WithString("path",
+				mcp.Description("Only list changes at or below this absolute path"),
+			),
+			mcp.WithNumber("max_entries",
+				mcp.Description("Maximum number of changes to return, in path order (default 1000, 0 for no limit)"),
+			),
+		),
+		s.handler.HandleContainerDiff,
+	)
+
+	// Container ports tool
+	s.addTool(
+		mcp.NewTool("container_port",
+			mcp.WithDescription("List the ports a container exposes and the host addresses each is published on, with host ports chosen by the daemon resolved."),
+			mcp.WithString("container_id",
+				mcp.Description("Container ID or name"),
+				mcp.Required(),
+			),
+			mcp.WithString("port",
+				mcp.Description("Only show this container port, e.g. \"80\" or \"53/udp\""),
+			),
+		),
+		s.handler.HandleContainerPort,
+	)
+
 	// Inspect image tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("inspect_image",
 			mcp.WithDescription("Return detailed information about an image."),
 			mcp.WithString("image",
 				mcp.Description("Image ID or name to inspect"),
 				mcp.Required(),
 			),
+			mcp.WithBoolean("redact",
+				mcp.Description("Mask secrets such as password and token variables and credential formats in the output. Set false to return the output unmasked"),
+				mcp.DefaultBool(true),
+			),
 		),
 		s.handler.HandleInspectImage,
 	)
 
 	// Build image tool
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcp.NewTool("build_image",
-			mcp.WithDescription("Build an image from a Dockerfile."),
+			mcp.WithDescription("Build an image from a Dockerfile. Local contexts honour .dockerignore and are rejected before upload if larger than the server's limit. Returns the image ID, the uploaded context size and file count, and a per-step log from either the legacy builder or BuildKit; a failed build returns an error naming the failing step, followed by the last lines of its output."),
 			mcp.WithString("context_path",
-				mcp.Description("Path to the build context"),
-				mcp.Required(),
+				mcp.Description("Path to a build context directory on the server. Exactly one of context_path, context_url or dockerfile_content/files is required"),
+			),
+			mcp.WithString("context_url",
+				mcp.Description("Remote build context fetched by the daemon: a git repository (https://...git#ref:dir, git@...) or a tarball URL"),
+			),
+			mcp.WithString("dockerfile_content",
+				mcp.Description("Inline Dockerfile body. The server packs it, together with files, into an in-memory build context"),
+			),
+			mcp.WithObject("files",
+				mcp.Description("Additional files for an inline build context, keyed by relative path. Each value is the file's text, or {\"content\", \"encoding\": \"utf-8|base64\", \"mode\": octal string}"),
 			),
 			mcp.WithString("dockerfile",
-				mcp.Description("Name of the Dockerfile"),
+				mcp.Description("Path of the Dockerfile within the context"),
 				mcp.DefaultString("Dockerfile"),
 			),
 			mcp.WithString("tag",
-				mcp.Description("Tag to apply to the built image"),
-				mcp.Required(),
+				mcp.Description("Tag to apply to the built image. Either tag or tags is required"),
+			),
+			mcp.WithArray("tags",
+				mcp.Description("Tags to apply to the built image (array of name:tag strings)"),
 			),
 			mcp.WithBoolean("no_cache",
 				mcp.Description("Do not use cache when building the image"),
@@ -310,14 +763,80 @@ func (s *DockerMCPServer) registerTools() error {
 				mcp.Description("Always attempt to pull a newer version of parent images"),
 				mcp.DefaultBool(false),
 			),
+			mcp.WithObject("build_args",
+				mcp.Description("Build-time variables for ARG instructions (object of name to value)"),
+			),
+			mcp.WithString("target",
+				mcp.Description("Build stage to stop at in a multi-stage Dockerfile"),
+			),
+			mcp.WithString("platform",
+				mcp.Description("Target platform, e.g. linux/amd64 or linux/arm64"),
+			),
+			mcp.WithObject("labels",
+				mcp.Description("Labels to set on the image (object of key to value)"),
+			),
+			mcp.WithString("network",
+				mcp.Description("Network mode for RUN instructions, e.g. host or none"),
+			),
+			mcp.WithArray("extra_hosts",
+				mcp.Description("Extra /etc/hosts entries for RUN instructions (array of host:ip strings)"),
+			),
+			mcp.WithArray("cache_from",
+				mcp.Description("Images to consider as cache sources (array of strings)"),
+			),
+			mcp.WithNumber("shm_size",
+				mcp.Description("Size of /dev/shm for RUN instructions in bytes"),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.Description("Validate the request against the daemon and return the planned action without performing it"),
+			),
 		),
 		s.handler.HandleBuildImage,
 	)
 
+	s.registerNetworkTools()
+	s.registerVolumeTools()
+
 	slog.Info("All tools registered successfully")
 	return nil
 }
 
+// addTool registers a tool with the MCP server unless the tool filter disables it.
+// The handler is wrapped so every call is logged with the principal from the
+// request context and, when enabled, written to the audit log.
+func (s *DockerMCPServer) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	s.defined[tool.Name] = true
+	if !s.tools.enabled(tool.Name) {
+		slog.Debug("Tool disabled by configuration", "tool", tool.Name)
+		return
+	}
+
+	if s.dryRun && !readOnlyTools[tool.Name] && !plannable(tool) {
+		handler = refuseDryRun(tool.Name)
+	}
+	if s.audit != nil {
+		handler = auditMiddleware(s.audit, s.redactor, tool.Name, handler)
+	}
+	s.mcpServer.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		principal, _ := auth.FromContext(ctx)
+		slog.Debug("Handling tool call", "tool", tool.Name, "principal", principal.String())
+		return handler(ctx, request)
+	})
+}
+
+// refuseDryRun returns a handler that fails every call of a tool that cannot be planned in dry-run mode
+func refuseDryRun(name string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		response := models.APIResponse{
+			Success:   false,
+			Error:     fmt.Sprintf("%s cannot be planned and is refused while the server runs in dry-run mode", name),
+			Timestamp: time.Now(),
+		}
+		responseJSON, _ := json.MarshalIndent(response, "", "  ")
+		return mcp.NewToolResultText(string(responseJSON)), nil
+	}
+}
+
 // GetMCPServer returns the underlying MCP server
 func (s *DockerMCPServer) GetMCPServer() *server.MCPServer {
 	return s.mcpServer
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sessionOwners records the principal that owns each session of a network
+// transport, so that a session ID leaked to another caller cannot be used by it
+type sessionOwners struct {
+	mu     sync.Mutex
+	owners map[string]string // session ID -> owning principal
+}
+
+// newSessionOwners creates an empty session registry
+func newSessionOwners() *sessionOwners {
+	return &sessionOwners{owners: make(map[string]string)}
+}
+
+// add records principal as the owner of the session
+func (o *sessionOwners) add(sessionID, principal string) {
+	o.mu.Lock()
+	o.owners[sessionID] = principal
+	o.mu.Unlock()
+}
+
+// remove forgets the session
+func (o *sessionOwners) remove(sessionID string) {
+	o.mu.Lock()
+	delete(o.owners, sessionID)
+	o.mu.Unlock()
+}
+
+// owns reports whether the session ID is known and owned by principal
+func (o *sessionOwners) owns(sessionID, principal string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	owner, ok := o.owners[sessionID]
+	return ok && owner == principal
+}
+
+// sseSessionBinder binds each SSE session to the principal that opened its
+// event stream and refuses messages posted to it by any other principal
+type sseSessionBinder struct {
+	next        http.Handler
+	ssePath     string
+	messagePath string
+	sessions    *sessionOwners
+}
+
+// newSSESessionBinder wraps an SSE handler serving the given stream and message paths
+func newSSESessionBinder(next http.Handler, ssePath, messagePath string) *sseSessionBinder {
+	return &sseSessionBinder{
+		next:        next,
+		ssePath:     ssePath,
+		messagePath: messagePath,
+		sessions:    newSessionOwners(),
+	}
+}
+
+// ServeHTTP implements the http.Handler interface
+func (b *sseSessionBinder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	principal, _ := auth.FromContext(r.Context())
+
+	switch r.URL.Path {
+	case b.ssePath:
+		// The session ID is only known once the endpoint event is written
+		sw := &sseEndpointWriter{ResponseWriter: w, bind: func(sessionID string) {
+			b.sessions.add(sessionID, principal.String())
+		}}
+		defer func() {
+			if sw.sessionID != "" {
+				b.sessions.remove(sw.sessionID)
+			}
+		}()
+		b.next.ServeHTTP(sw, r)
+	case b.messagePath:
+		sessionID := r.URL.Query().Get("sessionId")
+		if sessionID != "" && !b.sessions.owns(sessionID, principal.String()) {
+			writeJSONRPCError(w, http.StatusNotFound, mcp.INVALID_REQUEST, "Unknown session")
+			return
+		}
+		b.next.ServeHTTP(w, r)
+	default:
+		b.next.ServeHTTP(w, r)
+	}
+}
+
+// sseEndpointWriter reads the session ID from the endpoint event that starts
+// an SSE stream and binds it before the event reaches the client
+type sseEndpointWriter struct {
+	http.ResponseWriter
+	bind      func(sessionID string)
+	sessionID string
+}
+
+// Write implements the http.ResponseWriter interface
+func (w *sseEndpointWriter) Write(p []byte) (int, error) {
+	if w.sessionID == "" && bytes.HasPrefix(p, []byte("event: endpoint\n")) {
+		if sessionID := endpointSessionID(p); sessionID != "" {
+			w.sessionID = sessionID
+			w.bind(sessionID)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements the http.Flusher interface the SSE server requires
+func (w *sseEndpointWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// endpointSessionID extracts the sessionId query parameter from the data of an endpoint event
+func endpointSessionID(event []byte) string {
+	for _, line := range strings.Split(string(event), "\n") {
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
+		}
+		endpoint, err := url.Parse(data)
+		if err != nil {
+			return ""
+		}
+		return endpoint.Query().Get("sessionId")
+	}
+	return ""
+}
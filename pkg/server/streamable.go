@@ -0,0 +1,271 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+	"github.com/coolbit-in/docker-mcp/pkg/notify"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// sessionIDHeader carries the session identifier assigned during initialization
+const sessionIDHeader = "Mcp-Session-Id"
+
+// maxRequestBodySize limits the size of a single POSTed JSON-RPC payload
+const maxRequestBodySize = 32 << 20
+
+// streamableHTTPHandler implements the MCP streamable HTTP transport.
+// Every POST carries one JSON-RPC message (or a batch) and receives the
+// responses in the HTTP response body, as JSON or, when the client accepts
+// it, as an event stream that also carries notifications sent while the
+// requests run. Sessions are tracked via the Mcp-Session-Id header and
+// bound to the principal that created them.
+type streamableHTTPHandler struct {
+	mcpServer *server.MCPServer
+	endpoint  string
+	canceller *requestCanceller
+	sessions  *sessionOwners
+}
+
+// newStreamableHTTPHandler creates a streamable HTTP handler serving the given endpoint path
+func newStreamableHTTPHandler(mcpServer *server.MCPServer, endpoint string) *streamableHTTPHandler {
+	return &streamableHTTPHandler{
+		mcpServer: mcpServer,
+		endpoint:  endpoint,
+		canceller: newRequestCanceller(),
+		sessions:  newSessionOwners(),
+	}
+}
+
+// ServeHTTP implements the http.Handler interface
+func (h *streamableHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != h.endpoint {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		// Server-initiated streams (GET) are not offered by this server
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost processes JSON-RPC messages posted by the client
+func (h *streamableHTTPHandler) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err != nil {
+		writeJSONRPCError(w, http.StatusRequestEntityTooLarge, mcp.INVALID_REQUEST, "Request body too large")
+		return
+	}
+
+	messages, batch, err := splitJSONRPCBatch(body)
+	if err != nil {
+		writeJSONRPCError(w, http.StatusBadRequest, mcp.PARSE_ERROR, "Parse error")
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	sessionID := r.Header.Get(sessionIDHeader)
+	if containsInitialize(messages) {
+		sessionID, err = h.newSession(principal.String())
+		if err != nil {
+			writeJSONRPCError(w, http.StatusInternalServerError, mcp.INTERNAL_ERROR, "Failed to create session")
+			return
+		}
+		w.Header().Set(sessionIDHeader, sessionID)
+	} else if sessionID == "" {
+		writeJSONRPCError(w, http.StatusBadRequest, mcp.INVALID_REQUEST, "Missing "+sessionIDHeader+" header")
+		return
+	} else if !h.sessions.owns(sessionID, principal.String()) {
+		writeJSONRPCError(w, http.StatusNotFound, mcp.INVALID_REQUEST, "Unknown session")
+		return
+	}
+
+	if acceptsEventStream(r) && containsRequest(messages) {
+		h.streamResponses(w, r, sessionID, messages)
+		return
+	}
+
+	// Plain JSON responses cannot carry notifications
+	ctx := notify.NewContext(r.Context(), notify.Discard)
+	var responses []mcp.JSONRPCMessage
+	for _, message := range messages {
+		if response := h.handleMessage(ctx, sessionID, message); response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	// Notifications and responses from the client produce no reply
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		json.NewEncoder(w).Encode(responses)
+	} else {
+		json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+// streamResponses answers with an event stream carrying the notifications
+// sent while the messages are handled, followed by each response
+func (h *streamableHTTPHandler) streamResponses(w http.ResponseWriter, r *http.Request, sessionID string, messages []json.RawMessage) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSONRPCError(w, http.StatusInternalServerError, mcp.INTERNAL_ERROR, "Streaming unsupported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var mu sync.Mutex
+	writeEvent := func(message interface{}) error {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	ctx := notify.NewContext(r.Context(), notify.NotifierFunc(func(method string, params map[string]interface{}) error {
+		return writeEvent(newNotification(method, params))
+	}))
+	for _, message := range messages {
+		if response := h.handleMessage(ctx, sessionID, message); response != nil {
+			writeEvent(response)
+		}
+	}
+}
+
+// handleMessage passes one message to the MCP server, making the request cancellable by the client
+func (h *streamableHTTPHandler) handleMessage(ctx context.Context, sessionID string, message json.RawMessage) mcp.JSONRPCMessage {
+	ctx, done := h.canceller.track(ctx, sessionID, message)
+	defer done()
+	return h.mcpServer.HandleMessage(ctx, message)
+}
+
+// handleDelete terminates the session named in the request header
+func (h *streamableHTTPHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get(sessionIDHeader)
+	if sessionID == "" {
+		http.Error(w, "Missing "+sessionIDHeader+" header", http.StatusBadRequest)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	if !h.sessions.owns(sessionID, principal.String()) {
+		http.Error(w, "Unknown session", http.StatusNotFound)
+		return
+	}
+
+	h.sessions.remove(sessionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newSession allocates a random session ID owned by principal
+func (h *streamableHTTPHandler) newSession(principal string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	sessionID := hex.EncodeToString(buf)
+
+	h.sessions.add(sessionID, principal)
+	return sessionID, nil
+}
+
+// splitJSONRPCBatch splits a request body into individual messages.
+// The batch flag reports whether the body was a JSON array.
+func splitJSONRPCBatch(body []byte) ([]json.RawMessage, bool, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var messages []json.RawMessage
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return nil, true, err
+		}
+		return messages, true, nil
+	}
+
+	var message json.RawMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, false, err
+	}
+	return []json.RawMessage{message}, false, nil
+}
+
+// containsRequest reports whether any message is a request expecting a response
+func containsRequest(messages []json.RawMessage) bool {
+	for _, message := range messages {
+		var env rpcEnvelope
+		if err := json.Unmarshal(message, &env); err == nil && len(env.ID) > 0 && env.Method != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsEventStream reports whether the client accepts an event stream response
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType, _, _ = strings.Cut(mediaType, ";")
+			if strings.TrimSpace(mediaType) == "text/event-stream" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsInitialize reports whether any message is an initialize request
+func containsInitialize(messages []json.RawMessage) bool {
+	for _, message := range messages {
+		var base struct {
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(message, &base); err == nil && base.Method == "initialize" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSONRPCError writes a JSON-RPC error without an ID using the given HTTP status
+func writeJSONRPCError(w http.ResponseWriter, status int, code int, message string) {
+	response := mcp.JSONRPCError{
+		JSONRPC: mcp.JSONRPC_VERSION,
+	}
+	response.Error.Code = code
+	response.Error.Message = message
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// readOnlyTools are the tools that never change daemon state. They are the
+// only tools registered in read-only mode.
+var readOnlyTools = map[string]bool{
+	"list_containers":   true,
+	"inspect_container": true,
+	"logs":              true,
+	"container_stats":   true,
+	"container_top":     true,
+	"container_diff":    true,
+	"container_port":    true,
+	"wait_container":    true,
+	"list_images":       true,
+	"search":            true,
+	"inspect_image":     true,
+	"list_networks":     true,
+	"inspect_network":   true,
+	"list_volumes":      true,
+	"inspect_volume":    true,
+}
+
+// plannable reports whether a tool accepts dry_run and so can be planned instead of performed
+func plannable(tool mcp.Tool) bool {
+	_, ok := tool.InputSchema.Properties["dry_run"]
+	return ok
+}
+
+// ToolFilter selects which tools the server exposes. Deny takes precedence
+// over Allow, and ReadOnly removes every mutating tool regardless of Allow.
+type ToolFilter struct {
+	ReadOnly bool     `yaml:"read_only"` // Expose only tools that do not change daemon state
+	Allow    []string `yaml:"allow"`     // If non-empty, expose only these tools
+	Deny     []string `yaml:"deny"`      // Never expose these tools
+}
+
+// LoadToolFilter reads a tool filter from a YAML or JSON file:
+//
+//	read_only: false
+//	allow: [list_containers, logs, exec_command]
+//	deny: [remove_image]
+func LoadToolFilter(path string) (ToolFilter, error) {
+	var filter ToolFilter
+
+	f, err := os.Open(path)
+	if err != nil {
+		return filter, fmt.Errorf("failed to open tool config: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&filter); err != nil {
+		return filter, fmt.Errorf("failed to parse tool config %s: %w", path, err)
+	}
+	return filter, nil
+}
+
+// Merge combines two filters: read-only if either is, with the union of their lists
+func (f ToolFilter) Merge(other ToolFilter) ToolFilter {
+	return ToolFilter{
+		ReadOnly: f.ReadOnly || other.ReadOnly,
+		Allow:    append(append([]string(nil), f.Allow...), other.Allow...),
+		Deny:     append(append([]string(nil), f.Deny...), other.Deny...),
+	}
+}
+
+// enabled reports whether the tool should be registered
+func (f ToolFilter) enabled(name string) bool {
+	if f.ReadOnly && !readOnlyTools[name] {
+		return false
+	}
+	for _, denied := range f.Deny {
+		if denied == name {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, allowed := range f.Allow {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validate rejects tool names that do not match any defined tool, which are most likely typos
+func (f ToolFilter) validate(defined map[string]bool) error {
+	var unknown []string
+	for _, name := range append(append([]string(nil), f.Allow...), f.Deny...) {
+		if !defined[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown tools in allow/deny list: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+	"github.com/coolbit-in/docker-mcp/pkg/notify"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Supported transport names
+const (
+	TransportStdio          = "stdio"
+	TransportSSE            = "sse"
+	TransportStreamableHTTP = "streamable-http"
+)
+
+// HTTPOptions configures the network transports (SSE and streamable HTTP)
+type HTTPOptions struct {
+	Addr            string        // Listen address (host:port)
+	BasePath        string        // Path prefix for all MCP endpoints
+	ShutdownTimeout time.Duration // Maximum time to wait for in-flight requests on shutdown
+
+	// Authenticator identifies callers; nil serves every request as auth.Anonymous
+	Authenticator auth.Authenticator
+	// TLSConfig enables HTTPS (and mutual TLS when it requires client certificates)
+	TLSConfig *tls.Config
+}
+
+// ServeStdio serves MCP over standard input/output until stdin is closed
+// or the process receives SIGINT/SIGTERM. Requests run as auth.Local.
+func (s *DockerMCPServer) ServeStdio() error {
+	return server.ServeStdio(s.mcpServer,
+		server.WithStdioContextFunc(func(ctx context.Context) context.Context {
+			return auth.NewContext(ctx, auth.Local)
+		}),
+	)
+}
+
+// ServeSSE serves MCP over the HTTP+SSE transport until ctx is cancelled.
+// Clients open an event stream at <base>/sse and post messages to <base>/message.
+func (s *DockerMCPServer) ServeSSE(ctx context.Context, opts HTTPOptions) error {
+	basePath := normalizeBasePath(opts.BasePath)
+	handler := s.sseHandler(basePath)
+
+	slog.Info("Serving MCP over SSE",
+		"addr", opts.Addr,
+		"sse_endpoint", basePath+"/sse",
+		"message_endpoint", basePath+"/message",
+	)
+	return serveHTTP(ctx, handler, opts)
+}
+
+// sseHandler builds the HTTP+SSE transport handler for the given base path.
+// Sessions are bound to the principal that opened their event stream.
+func (s *DockerMCPServer) sseHandler(basePath string) http.Handler {
+	// Notifications are queued on the caller's event stream rather than
+	// mcp-go's shared channel, which is not safe with several sessions
+	var sseServer *server.SSEServer
+	sseOpts := []server.SSEOption{
+		server.WithSSEContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+			sessionID := r.URL.Query().Get("sessionId")
+			return notify.NewContext(ctx, notify.NotifierFunc(func(method string, params map[string]interface{}) error {
+				return sseServer.SendEventToSession(sessionID, newNotification(method, params))
+			}))
+		}),
+	}
+	if basePath != "" {
+		sseOpts = append(sseOpts, server.WithBasePath(basePath))
+	}
+	sseServer = server.NewSSEServer(s.mcpServer, sseOpts...)
+	handler := newRequestCanceller().middleware(sseServer, func(r *http.Request) string {
+		return r.URL.Query().Get("sessionId")
+	})
+	return newSSESessionBinder(handler, basePath+"/sse", basePath+"/message")
+}
+
+// ServeStreamableHTTP serves MCP over the streamable HTTP transport until ctx is cancelled.
+// Clients exchange JSON-RPC messages with a single endpoint at <base>/mcp;
+// clients accepting text/event-stream also receive notifications sent while a request runs.
+func (s *DockerMCPServer) ServeStreamableHTTP(ctx context.Context, opts HTTPOptions) error {
+	basePath := normalizeBasePath(opts.BasePath)
+	handler := newStreamableHTTPHandler(s.mcpServer, basePath+"/mcp")
+
+	slog.Info("Serving MCP over streamable HTTP",
+		"addr", opts.Addr,
+		"endpoint", basePath+"/mcp",
+	)
+	return serveHTTP(ctx, handler, opts)
+}
+
+// serveHTTP runs an HTTP server for handler and shuts it down gracefully when ctx is cancelled.
+// Connections still open after the shutdown timeout (e.g. idle SSE streams) are closed forcibly.
+func serveHTTP(ctx context.Context, handler http.Handler, opts HTTPOptions) error {
+	if opts.Authenticator == nil {
+		slog.Warn("Serving without authentication; anyone who can reach the listen address controls the Docker daemon",
+			"addr", opts.Addr,
+		)
+	}
+
+	httpServer := &http.Server{
+		Addr:              opts.Addr,
+		Handler:           auth.Middleware(opts.Authenticator, handler),
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig:         opts.TLSConfig,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if opts.TLSConfig != nil {
+			// Certificates are already loaded into TLSConfig
+			errCh <- httpServer.ListenAndServeTLS("", "")
+		} else {
+			errCh <- httpServer.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("http server error: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down MCP server", "timeout", opts.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Graceful shutdown did not complete, closing remaining connections", "error", err)
+		if err := httpServer.Close(); err != nil {
+			return fmt.Errorf("failed to close http server: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// newNotification builds a JSON-RPC notification message
+func newNotification(method string, params map[string]interface{}) mcp.JSONRPCNotification {
+	return mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: method,
+			Params: mcp.NotificationParams{AdditionalFields: params},
+		},
+	}
+}
+
+// normalizeBasePath ensures the base path starts with a slash and has no trailing slash
+func normalizeBasePath(basePath string) string {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if basePath != "" && !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+	return basePath
+}
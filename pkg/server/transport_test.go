@@ -0,0 +1,265 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coolbit-in/docker-mcp/pkg/auth"
+	"github.com/coolbit-in/docker-mcp/pkg/models"
+)
+
+// initializeMessage is the JSON-RPC request that opens an MCP session
+const initializeMessage = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+
+// writeTokenFile writes a token file for alice and bob and returns an authenticator for it
+func writeTokenFile(t *testing.T) *auth.TokenAuthenticator {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tokens")
+	content := "# agents\nalice:alice-token\n\n  bob : bob-token  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := auth.LoadTokenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// postMessage posts a JSON-RPC message with the given bearer token and session ID (either may be empty)
+func postMessage(t *testing.T, url, token, sessionID, body string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if sessionID != "" {
+		req.Header.Set(sessionIDHeader, sessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(data)
+}
+
+// deleteSession sends a DELETE for the session with the given bearer token
+func deleteSession(t *testing.T, url, token, sessionID string) int {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(sessionIDHeader, sessionID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// TestStreamableHTTPSessions checks that sessions are created, reused and
+// deleted by the principal that created them and refused to anyone else
+func TestStreamableHTTPSessions(t *testing.T) {
+	env := newTestEnv(t)
+	ts := httptest.NewServer(auth.Middleware(writeTokenFile(t), newStreamableHTTPHandler(env.server.mcpServer, "/mcp")))
+	t.Cleanup(ts.Close)
+	endpoint := ts.URL + "/mcp"
+
+	resp, _ := postMessage(t, endpoint, "", "", initializeMessage)
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected an unauthenticated request to be refused, got %s", resp.Status)
+	}
+
+	resp, body := postMessage(t, endpoint, "alice-token", "", initializeMessage)
+	sessionID := resp.Header.Get(sessionIDHeader)
+	if resp.StatusCode != http.StatusOK || sessionID == "" || !strings.Contains(body, `"serverInfo"`) {
+		t.Fatalf("expected initialize to create a session, got %s %q", resp.Status, body)
+	}
+
+	listTools := `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`
+	resp, body = postMessage(t, endpoint, "alice-token", sessionID, listTools)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `"list_containers"`) {
+		t.Fatalf("expected the session to be reused, got %s %q", resp.Status, body)
+	}
+	if resp, _ := postMessage(t, endpoint, "alice-token", "", listTools); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected a request without a session to be refused, got %s", resp.Status)
+	}
+	if resp, _ := postMessage(t, endpoint, "alice-token", "unknown", listTools); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected an unknown session to be refused, got %s", resp.Status)
+	}
+
+	// Another principal can neither use nor delete the session
+	if resp, body := postMessage(t, endpoint, "bob-token", sessionID, listTools); resp.StatusCode != http.StatusNotFound || !strings.Contains(body, "Unknown session") {
+		t.Fatalf("expected bob to be refused alice's session, got %s %q", resp.Status, body)
+	}
+	if status := deleteSession(t, endpoint, "bob-token", sessionID); status != http.StatusNotFound {
+		t.Fatalf("expected bob to be refused deleting alice's session, got %d", status)
+	}
+
+	if status := deleteSession(t, endpoint, "alice-token", sessionID); status != http.StatusNoContent {
+		t.Fatalf("expected alice to delete her session, got %d", status)
+	}
+	if resp, _ := postMessage(t, endpoint, "alice-token", sessionID, listTools); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected the deleted session to be refused, got %s", resp.Status)
+	}
+}
+
+// TestSSESessions checks that SSE sessions are bound to the principal that opened the event stream
+func TestSSESessions(t *testing.T) {
+	env := newTestEnv(t)
+	ts := httptest.NewServer(auth.Middleware(writeTokenFile(t), env.server.sseHandler("")))
+	t.Cleanup(ts.Close)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/sse", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer alice-token")
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+
+	// The first event names the message endpoint of the session
+	var messagePath string
+	reader := bufio.NewReader(stream.Body)
+	for messagePath == "" {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read the endpoint event: %v", err)
+		}
+		if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+			messagePath = data
+		}
+	}
+	endpoint := ts.URL + messagePath
+
+	if resp, body := postMessage(t, endpoint, "alice-token", "", initializeMessage); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected alice to use her session, got %s %q", resp.Status, body)
+	}
+	if resp, body := postMessage(t, endpoint, "bob-token", "", initializeMessage); resp.StatusCode != http.StatusNotFound || !strings.Contains(body, "Unknown session") {
+		t.Fatalf("expected bob to be refused alice's session, got %s %q", resp.Status, body)
+	}
+
+	// Closing the stream ends the session
+	stream.Body.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, _ := postMessage(t, endpoint, "alice-token", "", initializeMessage)
+		if resp.StatusCode == http.StatusNotFound {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the closed session to be refused, got %s", resp.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestCancelledNotification checks that notifications/cancelled stops the
+// matching in-flight request of the same session only
+func TestCancelledNotification(t *testing.T) {
+	env := newTestEnv(t)
+	id := env.runContainer("app")
+	ts := httptest.NewServer(newStreamableHTTPHandler(env.server.mcpServer, "/mcp"))
+	t.Cleanup(ts.Close)
+	endpoint := ts.URL + "/mcp"
+
+	resp, _ := postMessage(t, endpoint, "", "", initializeMessage)
+	sessionID := resp.Header.Get(sessionIDHeader)
+	resp, _ = postMessage(t, endpoint, "", "", initializeMessage)
+	otherSession := resp.Header.Get(sessionIDHeader)
+
+	type result struct {
+		body    string
+		err     error
+		elapsed time.Duration
+	}
+	done := make(chan result, 1)
+	go func() {
+		start := time.Now()
+		call := fmt.Sprintf(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"logs","arguments":{"container_id":%q,"follow":true,"follow_duration":20}}}`, id)
+		req, _ := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(call))
+		req.Header.Set(sessionIDHeader, sessionID)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		done <- result{string(body), err, time.Since(start)}
+	}()
+
+	cancel := `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7}}`
+	time.Sleep(100 * time.Millisecond)
+	if resp, _ := postMessage(t, endpoint, "", otherSession, cancel); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected the notification to be accepted, got %s", resp.Status)
+	}
+	select {
+	case r := <-done:
+		t.Fatalf("another session cancelled the request: %s", r.body)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if resp, _ := postMessage(t, endpoint, "", sessionID, cancel); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected the notification to be accepted, got %s", resp.Status)
+	}
+	var r result
+	select {
+	case r = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the cancelled request did not return")
+	}
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+
+	var message struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(r.body), &message); err != nil || len(message.Result.Content) == 0 {
+		t.Fatalf("unexpected response %q: %v", r.body, err)
+	}
+	var response models.APIResponse
+	var logs models.LogsResponse
+	if err := json.Unmarshal([]byte(message.Result.Content[0].Text), &response); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(response.Data, &logs); err != nil {
+		t.Fatal(err)
+	}
+	if logs.Follow == nil || logs.Follow.StopReason != "cancelled" || r.elapsed > 5*time.Second {
+		t.Fatalf("expected the follow to be cancelled early, got %+v after %s", logs.Follow, r.elapsed)
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// registerVolumeTools registers the volume management tools with the MCP server
+func (s *DockerMCPServer) registerVolumeTools() {
+	// List volumes tool
+	s.addTool(
+		mcp.NewTool("list_volumes",
+			mcp.WithDescription("List Docker volumes with their names, drivers, mountpoints and labels. Returns array of volume objects."),
+			mcp.WithString("name",
+				mcp.Description("Only show volumes whose name contains this value"),
+			),
+			mcp.WithString("driver",
+				mcp.Description("Only show volumes using this driver"),
+			),
+			mcp.WithBoolean("dangling",
+				mcp.Description("Only show volumes that are (true) or are not (false) referenced by any container"),
+			),
+		),
+		s.handler.HandleListVolumes,
+	)
+
+	// Inspect volume tool
+	s.addTool(
+		mcp.NewTool("inspect_volume",
+			mcp.WithDescription("Return detailed information about a volume."),
+			mcp.WithString("name",
+				mcp.Description("Volume name to inspect"),
+				mcp.Required(),
+			),
+		),
+		s.handler.HandleInspectVolume,
+	)
+
+	// Create volume tool
+	s.addTool(
+		mcp.NewTool("create_volume",
+			mcp.WithDescription("Create a named volume. Returns the created volume."),
+			mcp.WithString("name",
+				mcp.Description("Volume name (generated by the daemon if omitted)"),
+			),
+			mcp.WithString("driver",
+				mcp.Description("Volume driver"),
+				mcp.DefaultString("local"),
+			),
+			mcp.WithObject("driver_opts",
+				mcp.Description("Driver specific options (format: {\"key\": \"value\"})"),
+			),
+			mcp.WithObject("labels",
+				mcp.Description("Volume labels (format: {\"key\": \"value\"})"),
+			),
+		),
+		s.handler.HandleCreateVolume,
+	)
+
+	// Remove volume tool
+	s.addTool(
+		mcp.NewTool("remove_volume",
+			mcp.WithDescription("Remove a volume."),
+			mcp.WithString("name",
+				mcp.Description("Volume name to remove"),
+				mcp.Required(),
+			),
+			mcp.WithBoolean("force",
+				mcp.Description("Force removal of the volume"),
+				mcp.DefaultBool(false),
+			),
+		),
+		s.handler.HandleRemoveVolume,
+	)
+
+	// Prune volumes tool
+	s.addTool(
+		mcp.NewTool("prune_volumes",
+			mcp.WithDescription("Remove volumes not used by any container. Returns the removed volume names and reclaimed space."),
+			mcp.WithBoolean("all",
+				mcp.Description("Also remove unused named volumes (default removes only anonymous volumes)"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithArray("labels",
+				mcp.Description("Only remove volumes with these labels (format: key or key=value)"),
+			),
+		),
+		s.handler.HandlePruneVolumes,
+	)
+}